@@ -24,7 +24,7 @@ var (
 func main() {
 	logger := log.GetLogger("example", &logOpt)
 
-	logger2 := log.NewFromLogger(logger).WithLoggerMetaFields(zap.String("version", "2"))
+	logger2 := logger.With(zap.String("version", "2"))
 
 	logger3 := log.GetLogger("example3", &noConsoleLogOpt)
 
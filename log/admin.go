@@ -0,0 +1,156 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerInfo describes a registered logger
+type LoggerInfo struct {
+	Name      string `json:"name"`
+	Level     string `json:"level"`
+	File      string `json:"file"`                 // empty when log file is disabled
+	ErrorFile string `json:"error_file,omitempty"` // empty when error log file is disabled
+	Console   bool   `json:"console"`
+	Otel      bool   `json:"otel"`
+}
+
+// LevelRequest is the body of a PUT request to the admin handler
+type LevelRequest struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	TTL   string `json:"ttl,omitempty"` // revert to the previous level after ttl, e.g. "10m"
+}
+
+// AdminHandler inspects and changes levels of registered loggers over http,
+// the zero value is ready to use
+//
+//	GET  lists every registered logger
+//	PUT  changes a logger level, body: {"name": "app", "level": "debug", "ttl": "10m"}
+type AdminHandler struct {
+	mu      sync.Mutex
+	reverts map[string]*levelRevert
+}
+
+// levelRevert restores a logger level when the ttl of a change expires
+type levelRevert struct {
+	timer *time.Timer
+	level zapcore.Level
+}
+
+// NewAdminHandler new a admin handler
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{reverts: make(map[string]*levelRevert)}
+}
+
+// Loggers list registered loggers sorted by name
+func Loggers() []LoggerInfo {
+	mu.Lock()
+	defer mu.Unlock()
+	infos := make([]LoggerInfo, 0, len(loggers))
+	for name, l := range loggers {
+		info := LoggerInfo{
+			Name:    name,
+			Level:   l.Level().String(),
+			Console: l.opt.ConsoleLogEnable,
+			Otel:    l.opt.LoggerProvider != nil,
+		}
+		if l.file != nil {
+			info.File = l.opt.GetLogFilePath()
+		}
+		if l.errorFile != nil {
+			info.ErrorFile = l.opt.GetErrorLogFilePath()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// ServeHTTP implements http.Handler
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, Loggers())
+	case http.MethodPut:
+		var req LevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		status, err := h.setLevel(req)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, http.StatusOK, Loggers())
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AdminHandler) setLevel(req LevelRequest) (int, error) {
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			return http.StatusBadRequest, fmt.Errorf("ttl: %s invalid", req.TTL)
+		}
+	}
+	mu.Lock()
+	l, ok := loggers[req.Name]
+	mu.Unlock()
+	if !ok {
+		return http.StatusNotFound, fmt.Errorf("logger: %s not found", req.Name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// a pending revert keeps the level from before the first temporary change
+	previous := l.Level()
+	if revert, ok := h.reverts[req.Name]; ok {
+		revert.timer.Stop()
+		previous = revert.level
+		delete(h.reverts, req.Name)
+	}
+	l.SetLevel(level)
+	if ttl > 0 {
+		revert := &levelRevert{level: previous}
+		revert.timer = time.AfterFunc(ttl, func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.reverts[req.Name] != revert { // replaced by a later change
+				return
+			}
+			delete(h.reverts, req.Name)
+			// the logger may be shut down or created again since the change
+			mu.Lock()
+			l, ok := loggers[req.Name]
+			mu.Unlock()
+			if ok {
+				l.SetLevel(revert.level)
+			}
+		})
+		if h.reverts == nil {
+			h.reverts = make(map[string]*levelRevert)
+		}
+		h.reverts[req.Name] = revert
+	}
+	return http.StatusOK, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
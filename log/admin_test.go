@@ -0,0 +1,82 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAdminHandler(t *testing.T) {
+	opt := CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false).WithLogLevel(zapcore.InfoLevel)
+	opt.IsDefault = false
+	logger := GetLogger("admin", &opt)
+	t.Cleanup(func() { _ = Shutdown(context.Background()) })
+	handler := NewAdminHandler()
+
+	// list loggers
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var infos []LoggerInfo
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&infos))
+	assert.Contains(t, infos, LoggerInfo{Name: "admin", Level: "info", File: logger.opt.GetLogFilePath()})
+
+	testcases := []struct {
+		Body string
+		Code int
+	}{
+		{`{"name": "admin", "level": "debug"}`, http.StatusOK},
+		{`{"name": "admin", "level": "verbose"}`, http.StatusBadRequest},
+		{`{"name": "admin", "level": "debug", "ttl": "-1s"}`, http.StatusBadRequest},
+		{`{"name": "not-exist", "level": "debug"}`, http.StatusNotFound},
+		{`not json`, http.StatusBadRequest},
+	}
+	for _, testcase := range testcases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(testcase.Body)))
+		assert.Equal(t, testcase.Code, rec.Code, testcase.Body)
+	}
+	assert.Equal(t, zapcore.DebugLevel, logger.Level())
+
+	// temporary change reverts to the level before the first temporary change
+	for _, body := range []string{
+		`{"name": "admin", "level": "warn", "ttl": "50ms"}`,
+		`{"name": "admin", "level": "error", "ttl": "50ms"}`,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, zapcore.ErrorLevel, logger.Level())
+	assert.Eventually(t, func() bool { return logger.Level() == zapcore.DebugLevel }, time.Second, 10*time.Millisecond)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAdminHandlerZeroValue(t *testing.T) {
+	t.Cleanup(func() { _ = Shutdown(context.Background()) })
+	opt := CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false).WithLogLevel(zapcore.InfoLevel)
+	opt.IsDefault = false
+	GetLogger("adminzero", &opt)
+	var handler AdminHandler
+
+	rec := httptest.NewRecorder()
+	body := `{"name": "adminzero", "level": "warn", "ttl": "50ms"}`
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the revert applies to the logger registered under the name when the ttl expires
+	assert.Nil(t, Shutdown(context.Background()))
+	recreated := GetLogger("adminzero", &opt)
+	recreated.SetLevel(zapcore.ErrorLevel)
+	assert.Eventually(t, func() bool { return recreated.Level() == zapcore.InfoLevel }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,271 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Config logger options loaded from a yaml, json or toml file
+//
+// yaml example:
+//
+//	loggers:
+//	  app:
+//	    level: debug
+//	    directory: /var/log/app
+//	    max_size: 15
+//	    console_log_enable: false
+type Config struct {
+	Loggers map[string]LoggerConfig `json:"loggers" yaml:"loggers" toml:"loggers"`
+}
+
+// LoggerConfig configures a named logger
+// fields not set in the file keep the value of CommonLogOpt
+type LoggerConfig struct {
+	Level            *zapcore.Level  `json:"level" yaml:"level" toml:"level"`
+	Directory        *string         `json:"directory" yaml:"directory" toml:"directory"`
+	TraceIDEnable    *bool           `json:"trace_id_enable" yaml:"trace_id_enable" toml:"trace_id_enable"`
+	MaxSize          *int            `json:"max_size" yaml:"max_size" toml:"max_size"`
+	MaxBackups       *int            `json:"max_backups" yaml:"max_backups" toml:"max_backups"`
+	MaxAge           *int            `json:"max_age" yaml:"max_age" toml:"max_age"`
+	IsDefault        *bool           `json:"is_default" yaml:"is_default" toml:"is_default"`
+	ConsoleLogEnable *bool           `json:"console_log_enable" yaml:"console_log_enable" toml:"console_log_enable"`
+	EnableCaller     *bool           `json:"enable_caller" yaml:"enable_caller" toml:"enable_caller"`
+	FileLevel        *zapcore.Level  `json:"file_level" yaml:"file_level" toml:"file_level"`
+	ConsoleLevel     *zapcore.Level  `json:"console_level" yaml:"console_level" toml:"console_level"`
+	FileEncoding     *Encoding       `json:"file_encoding" yaml:"file_encoding" toml:"file_encoding"`
+	ConsoleEncoding  *Encoding       `json:"console_encoding" yaml:"console_encoding" toml:"console_encoding"`
+	ConsoleColor     *ColorMode      `json:"console_color" yaml:"console_color" toml:"console_color"`
+	TimeFormat       *string         `json:"time_format" yaml:"time_format" toml:"time_format"`
+	CallerFormat     *CallerFormat   `json:"caller_format" yaml:"caller_format" toml:"caller_format"`
+	Rotation         *RotationPeriod `json:"rotation" yaml:"rotation" toml:"rotation"`
+	Compress         *bool           `json:"compress" yaml:"compress" toml:"compress"`
+	LocalTime        *bool           `json:"local_time" yaml:"local_time" toml:"local_time"`
+}
+
+// LoadConfig load logger config from file
+// the format is chosen by file extension: .yaml/.yml, .json or .toml
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ParseConfig parse logger config with format yaml, yml, json or toml
+func ParseConfig(data []byte, format string) (*Config, error) {
+	cfg := &Config{}
+	var err error
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, cfg)
+	case "json":
+		err = json.Unmarshal(data, cfg)
+	case "toml":
+		_, err = toml.Decode(string(data), cfg)
+	default:
+		return nil, fmt.Errorf("config format: %s not supported", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cfg.Loggers))
+	for name := range cfg.Loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := cfg.Loggers[name].validate(); err != nil {
+			return nil, fmt.Errorf("logger %s: %w", name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// validate rejects values the logger does not know,
+// which would otherwise silently fall back to defaults
+func (lc LoggerConfig) validate() error {
+	if lc.FileEncoding != nil && !lc.FileEncoding.valid() {
+		return fmt.Errorf("file_encoding: %s invalid", *lc.FileEncoding)
+	}
+	if lc.ConsoleEncoding != nil && !lc.ConsoleEncoding.valid() {
+		return fmt.Errorf("console_encoding: %s invalid", *lc.ConsoleEncoding)
+	}
+	if lc.ConsoleColor != nil && !lc.ConsoleColor.valid() {
+		return fmt.Errorf("console_color: %s invalid", *lc.ConsoleColor)
+	}
+	if lc.CallerFormat != nil && !lc.CallerFormat.valid() {
+		return fmt.Errorf("caller_format: %s invalid", *lc.CallerFormat)
+	}
+	if lc.Rotation != nil && !lc.Rotation.valid() {
+		return fmt.Errorf("rotation: %s invalid", *lc.Rotation)
+	}
+	return nil
+}
+
+// LoggerOpt build options for the named logger,
+// the second value reports whether the logger is present in the config
+func (c *Config) LoggerOpt(name string) (LoggerOpt, bool) {
+	lc, ok := c.Loggers[name]
+	opt := CommonLogOpt
+	// only loggers marked with is_default replace the default logger
+	opt.IsDefault = false
+	return lc.apply(opt), ok
+}
+
+func (lc LoggerConfig) apply(opt LoggerOpt) LoggerOpt {
+	if lc.Level != nil {
+		opt.LogLevel = *lc.Level
+	}
+	if lc.Directory != nil {
+		opt.Directory = *lc.Directory
+	}
+	if lc.TraceIDEnable != nil {
+		opt.TraceIDEnable = *lc.TraceIDEnable
+	}
+	if lc.MaxSize != nil {
+		opt.MaxSize = *lc.MaxSize
+	}
+	if lc.MaxBackups != nil {
+		opt.MaxBackups = *lc.MaxBackups
+	}
+	if lc.MaxAge != nil {
+		opt.MaxAge = *lc.MaxAge
+	}
+	if lc.IsDefault != nil {
+		opt.IsDefault = *lc.IsDefault
+	}
+	if lc.ConsoleLogEnable != nil {
+		opt.ConsoleLogEnable = *lc.ConsoleLogEnable
+	}
+	if lc.EnableCaller != nil {
+		opt.EnableCaller = *lc.EnableCaller
+	}
+	if lc.FileLevel != nil {
+		opt = opt.WithFileLevel(*lc.FileLevel)
+	}
+	if lc.ConsoleLevel != nil {
+		opt = opt.WithConsoleLevel(*lc.ConsoleLevel)
+	}
+	if lc.FileEncoding != nil {
+		opt.FileEncoding = *lc.FileEncoding
+	}
+	if lc.ConsoleEncoding != nil {
+		opt.ConsoleEncoding = *lc.ConsoleEncoding
+	}
+	if lc.ConsoleColor != nil {
+		opt.ConsoleColor = *lc.ConsoleColor
+	}
+	if lc.TimeFormat != nil {
+		opt.TimeFormat = *lc.TimeFormat
+	}
+	if lc.CallerFormat != nil {
+		opt.CallerFormat = *lc.CallerFormat
+	}
+	if lc.Rotation != nil {
+		opt.Rotation = *lc.Rotation
+	}
+	if lc.Compress != nil {
+		opt.Compress = *lc.Compress
+	}
+	if lc.LocalTime != nil {
+		opt.LocalTime = *lc.LocalTime
+	}
+	return opt
+}
+
+// ApplyConfig re-applies level and retention to the registered loggers,
+// loggers not yet created by GetLogger are skipped
+// and other options only take effect when a logger is created
+func ApplyConfig(cfg *Config) error {
+	mu.Lock()
+	defer mu.Unlock()
+	var errs []error
+	for name, lc := range cfg.Loggers {
+		l, ok := loggers[name]
+		if !ok {
+			continue
+		}
+		if lc.Level != nil {
+			l.SetLevel(*lc.Level)
+		}
+		if l.file == nil {
+			continue
+		}
+		maxSize, maxBackups, maxAge := l.file.retention()
+		if lc.MaxSize != nil {
+			maxSize = *lc.MaxSize
+		}
+		if lc.MaxBackups != nil {
+			maxBackups = *lc.MaxBackups
+		}
+		if lc.MaxAge != nil {
+			maxAge = *lc.MaxAge
+		}
+		if err := l.SetRetention(maxSize, maxBackups, maxAge); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WatchConfig load the config file then polls it every interval,
+// re-applying the config with ApplyConfig whenever the file changes until ctx is done.
+// Errors met while reloading are passed to onError when it is not nil, interval must be positive
+func WatchConfig(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval: %s invalid", interval)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := reloadConfig(path); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		modTime, size := stat.ModTime(), stat.Size()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			stat, err := os.Stat(path)
+			if err == nil {
+				if stat.ModTime().Equal(modTime) && stat.Size() == size {
+					continue
+				}
+				modTime, size = stat.ModTime(), stat.Size()
+				err = reloadConfig(path)
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+	return nil
+}
+
+func reloadConfig(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return ApplyConfig(cfg)
+}
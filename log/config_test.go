@@ -0,0 +1,96 @@
+package log
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseConfig(t *testing.T) {
+	testcases := []struct {
+		Format string
+		Data   string
+	}{
+		{"yaml", "loggers:\n  app:\n    level: debug\n    max_size: 20\n    console_log_enable: false\n" +
+			"    console_level: warn\n    file_encoding: logfmt\n"},
+		{"json", `{"loggers": {"app": {"level": "debug", "max_size": 20, "console_log_enable": false, ` +
+			`"console_level": "warn", "file_encoding": "logfmt"}}}`},
+		{"toml", "[loggers.app]\nlevel = \"debug\"\nmax_size = 20\nconsole_log_enable = false\n" +
+			"console_level = \"warn\"\nfile_encoding = \"logfmt\"\n"},
+	}
+
+	for _, testcase := range testcases {
+		cfg, err := ParseConfig([]byte(testcase.Data), testcase.Format)
+		assert.Nil(t, err, testcase.Format)
+		opt, ok := cfg.LoggerOpt("app")
+		assert.True(t, ok, testcase.Format)
+		assert.Equal(t, zapcore.DebugLevel, opt.LogLevel, testcase.Format)
+		assert.Equal(t, 20, opt.MaxSize, testcase.Format)
+		assert.Equal(t, false, opt.ConsoleLogEnable, testcase.Format)
+		assert.Equal(t, zapcore.WarnLevel, *opt.ConsoleLevel, testcase.Format)
+		assert.Equal(t, EncodingLogfmt, opt.FileEncoding, testcase.Format)
+		assert.Nil(t, opt.FileLevel, testcase.Format)
+		// unset fields keep common options
+		assert.Equal(t, CommonLogOpt.MaxBackups, opt.MaxBackups, testcase.Format)
+		assert.Equal(t, false, opt.IsDefault, testcase.Format)
+	}
+
+	_, err := ParseConfig([]byte(""), "ini")
+	assert.NotNil(t, err)
+}
+
+func TestParseConfigInvalidValue(t *testing.T) {
+	testcases := []struct {
+		Data string
+		Want string
+	}{
+		{"loggers:\n  app:\n    rotation: weekly\n", "logger app: rotation: weekly invalid"},
+		{"loggers:\n  app:\n    file_encoding: xml\n", "logger app: file_encoding: xml invalid"},
+		{"loggers:\n  app:\n    console_encoding: text\n", "logger app: console_encoding: text invalid"},
+		{"loggers:\n  app:\n    console_color: sometimes\n", "logger app: console_color: sometimes invalid"},
+		{"loggers:\n  app:\n    caller_format: long\n", "logger app: caller_format: long invalid"},
+	}
+
+	for _, testcase := range testcases {
+		_, err := ParseConfig([]byte(testcase.Data), "yaml")
+		assert.EqualError(t, err, testcase.Want)
+	}
+
+	_, err := ParseConfig([]byte("loggers:\n  app:\n    rotation: hourly\n    console_color: auto\n"), "yaml")
+	assert.Nil(t, err)
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("loggers:\n  watched:\n    level: info\n"), 0o644))
+
+	cfg, err := LoadConfig(path)
+	assert.Nil(t, err)
+	opt, _ := cfg.LoggerOpt("watched")
+	opt = opt.WithDirectory(dir).WithConsoleLog(false)
+	logger := GetLogger("watched", &opt)
+	t.Cleanup(func() { _ = Shutdown(context.Background()) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.EqualError(t, WatchConfig(ctx, path, 0, nil), "watch interval: 0s invalid")
+	// the watcher may still poll while the temp dir is removed after cancel
+	assert.Nil(t, WatchConfig(ctx, path, 10*time.Millisecond, func(err error) {
+		if ctx.Err() == nil {
+			t.Error(err)
+		}
+	}))
+
+	content := "loggers:\n  watched:\n    level: error\n    max_size: 1\n    max_backups: 2\n    max_age: 3\n"
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	assert.Eventually(t, func() bool {
+		maxSize, maxBackups, maxAge := logger.file.retention()
+		return logger.Level() == zapcore.ErrorLevel && maxSize == 1 && maxBackups == 2 && maxAge == 3
+	}, time.Second, 10*time.Millisecond)
+}
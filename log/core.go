@@ -0,0 +1,89 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// levelCore filters entries of a core it does not own the level of
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func newLevelCore(core zapcore.Core, level zapcore.LevelEnabler) zapcore.Core {
+	return &levelCore{Core: core, level: level}
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check implements zapcore.Core
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+// levelWriter writes encoded entries knowing their level, e.g. to set the syslog severity
+type levelWriter interface {
+	WriteLevel(level zapcore.Level, p []byte) error
+	Sync() error
+}
+
+// levelWriterCore is a zapcore.Core writing encoded entries to a levelWriter
+type levelWriterCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out levelWriter
+}
+
+func newLevelWriterCore(enc zapcore.Encoder, out levelWriter, level zapcore.LevelEnabler) zapcore.Core {
+	return &levelWriterCore{LevelEnabler: level, enc: enc, out: out}
+}
+
+// With implements zapcore.Core
+func (c *levelWriterCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &levelWriterCore{LevelEnabler: c.LevelEnabler, enc: enc, out: c.out}
+}
+
+// Check implements zapcore.Core
+func (c *levelWriterCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *levelWriterCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	if err := c.out.WriteLevel(entry.Level, buf.Bytes()); err != nil {
+		return err
+	}
+	if entry.Level > zapcore.ErrorLevel {
+		// entries above error may end the process
+		return c.Sync()
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *levelWriterCore) Sync() error {
+	return c.out.Sync()
+}
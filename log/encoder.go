@@ -0,0 +1,126 @@
+package log
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Encoding log line format of a log destination
+type Encoding string
+
+const (
+	// EncodingJSON one json object per line
+	EncodingJSON Encoding = "json"
+	// EncodingConsole human readable line
+	EncodingConsole Encoding = "console"
+	// EncodingLogfmt key=value pairs per line
+	EncodingLogfmt Encoding = "logfmt"
+)
+
+// ColorMode decides whether console log levels are colored
+type ColorMode string
+
+const (
+	// ColorAuto colors when stdout is a terminal and NO_COLOR is not set, the default
+	ColorAuto ColorMode = ""
+	// ColorAlways always colors
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors
+	ColorNever ColorMode = "never"
+)
+
+// CallerFormat format of the caller field
+type CallerFormat string
+
+const (
+	// CallerShort package/file.go:line
+	CallerShort CallerFormat = "short"
+	// CallerFull full file path with line
+	CallerFull CallerFormat = "full"
+)
+
+// valid reports whether e is a known encoding, empty uses the destination default
+func (e Encoding) valid() bool {
+	switch e {
+	case "", EncodingJSON, EncodingConsole, EncodingLogfmt:
+		return true
+	}
+	return false
+}
+
+// valid reports whether m is a known color mode, "auto" is accepted for ColorAuto
+func (m ColorMode) valid() bool {
+	switch m {
+	case ColorAuto, "auto", ColorAlways, ColorNever:
+		return true
+	}
+	return false
+}
+
+// valid reports whether f is a known caller format, empty keeps the encoding default
+func (f CallerFormat) valid() bool {
+	switch f {
+	case "", CallerShort, CallerFull:
+		return true
+	}
+	return false
+}
+
+// newEncoder new an encoder of encoding, with time and caller formats of opt applied
+func newEncoder(encoding Encoding, opt *LoggerOpt, color bool) zapcore.Encoder {
+	var cfg zapcore.EncoderConfig
+	switch encoding {
+	case EncodingConsole:
+		cfg = logConsoleEncodeCfg
+		if !color {
+			cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+	case EncodingLogfmt:
+		cfg = logLogfmtEncodeCfg
+	default:
+		cfg = logJsonEncodeCfg
+	}
+	if opt.TimeFormat != "" {
+		cfg.EncodeTime = zapcore.TimeEncoderOfLayout(opt.TimeFormat)
+	}
+	switch opt.CallerFormat {
+	case CallerShort:
+		cfg.EncodeCaller = zapcore.ShortCallerEncoder
+	case CallerFull:
+		cfg.EncodeCaller = zapcore.FullCallerEncoder
+	}
+
+	switch encoding {
+	case EncodingConsole:
+		return zapcore.NewConsoleEncoder(cfg)
+	case EncodingLogfmt:
+		return newLogfmtEncoder(cfg)
+	default:
+		return zapcore.NewJSONEncoder(cfg)
+	}
+}
+
+// useColor reports whether to color log written to f
+func useColor(mode ColorMode, f *os.File) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	// https://no-color.org
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
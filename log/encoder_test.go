@@ -0,0 +1,53 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestUseColor(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	assert.Nil(t, err)
+	defer f.Close()
+
+	assert.False(t, useColor(ColorAuto, f))
+	assert.True(t, useColor(ColorAlways, f))
+	assert.False(t, useColor(ColorNever, f))
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, useColor(ColorAuto, os.Stdout))
+}
+
+func TestNewEncoder(t *testing.T) {
+	entry := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "msg",
+		Caller:  zapcore.NewEntryCaller(0, "/src/app/main.go", 10, true),
+	}
+	testcases := []struct {
+		Encoding Encoding
+		Opt      LoggerOpt
+		Color    bool
+		Want     string
+	}{
+		{EncodingConsole, LoggerOpt{}, false, "2024-01-02T03:04:05Z\tWARN\tapp/main.go:10\tmsg\n"},
+		{EncodingConsole, LoggerOpt{}, true, "2024-01-02T03:04:05Z\t\x1b[33mWARN\x1b[0m\tapp/main.go:10\tmsg\n"},
+		{EncodingConsole, LoggerOpt{TimeFormat: time.DateTime, CallerFormat: CallerFull}, false,
+			"2024-01-02 03:04:05\tWARN\t/src/app/main.go:10\tmsg\n"},
+		{EncodingLogfmt, LoggerOpt{TimeFormat: time.DateOnly}, false,
+			"time=2024-01-02 level=warn caller=app/main.go:10 msg=msg\n"},
+		{EncodingJSON, LoggerOpt{CallerFormat: CallerShort}, false,
+			`{"level":"warn","time":"2024-01-02T03:04:05Z","caller":"app/main.go:10","msg":"msg"}` + "\n"},
+	}
+
+	for _, testcase := range testcases {
+		buf, err := newEncoder(testcase.Encoding, &testcase.Opt, testcase.Color).EncodeEntry(entry, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, testcase.Want, buf.String())
+	}
+}
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SentinelLevel logs errors matching Err at Level instead of error
+type SentinelLevel struct {
+	Err   error
+	Level zapcore.Level
+}
+
+// DefaultSentinelLevels are used by Logger.Err when LoggerOpt.SentinelLevels is nil,
+// canceled requests are expected and timeouts are usually retried
+var DefaultSentinelLevels = []SentinelLevel{
+	{Err: context.Canceled, Level: zapcore.InfoLevel},
+	{Err: context.DeadlineExceeded, Level: zapcore.WarnLevel},
+}
+
+// Err logs err with msg at error level, or at a lower level
+// when the errors at the end of its chains all match SentinelLevels.
+// Besides the "error" field, the errors of err, unwrapped through %w and errors.Join,
+// are logged in an "errors" array with their type and the stack trace they carry
+func (l *Logger) Err(ctx context.Context, err error, msg string, fields ...zap.Field) {
+	level := zapcore.ErrorLevel
+	if err != nil {
+		level = l.errorLevel(err)
+		fields = append(fields[:len(fields):len(fields)], zap.Error(err), zap.Array("errors", l.errorChain(err)))
+	}
+	l.log(ctx, level, l.levelFunc, msg, fields...)
+}
+
+// errorLevel returns the highest level of the errors at the end of err chains,
+// so a real failure joined with a canceled context is still logged at error level
+func (l *Logger) errorLevel(err error) zapcore.Level {
+	levels := l.opt.SentinelLevels
+	if levels == nil {
+		levels = DefaultSentinelLevels
+	}
+	level := zapcore.DebugLevel
+	walkErrors(err, func(e error) {
+		if unwrappable(e) {
+			return
+		}
+		leafLevel := zapcore.ErrorLevel
+		// later entries take precedence
+		for i := len(levels) - 1; i >= 0; i-- {
+			if errors.Is(e, levels[i].Err) {
+				leafLevel = levels[i].Level
+				break
+			}
+		}
+		if leafLevel > level {
+			level = leafLevel
+		}
+	})
+	return level
+}
+
+// errorChain returns the errors of err in depth first order
+func (l *Logger) errorChain(err error) zapcore.ArrayMarshaler {
+	var chain errorArray
+	walkErrors(err, func(e error) {
+		chain = append(chain, errorInfo{
+			message: l.redact.message(e.Error()),
+			typ:     fmt.Sprintf("%T", e),
+			stack:   errorStack(e),
+		})
+	})
+	return chain
+}
+
+// walkErrors calls fn with err and every error it wraps, depth first
+func walkErrors(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrors(e.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			walkErrors(inner, fn)
+		}
+	}
+}
+
+// unwrappable reports whether err wraps other errors
+func unwrappable(err error) bool {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap() != nil
+	case interface{ Unwrap() []error }:
+		return len(e.Unwrap()) > 0
+	}
+	return false
+}
+
+// errorStack formats the stack trace carried by err, empty when err carries none.
+// A StackTrace method returning a string or a slice of program counters is supported,
+// which covers github.com/pkg/errors and similar packages without depending on them
+func errorStack(err error) string {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+	trace := method.Call(nil)[0]
+	switch {
+	case trace.Kind() == reflect.String:
+		return trace.String()
+	case trace.Kind() == reflect.Slice && trace.Type().Elem().Kind() == reflect.Uintptr:
+		pcs := make([]uintptr, trace.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(trace.Index(i).Uint())
+		}
+		return formatFrames(pcs)
+	}
+	return ""
+}
+
+// formatFrames formats program counters the way zap formats stack traces
+func formatFrames(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if !more {
+			return b.String()
+		}
+	}
+}
+
+// errorInfo is an element of the errors array
+type errorInfo struct {
+	message string
+	typ     string
+	stack   string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (e errorInfo) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.message)
+	enc.AddString("type", e.typ)
+	if e.stack != "" {
+		enc.AddString("stack", e.stack)
+	}
+	return nil
+}
+
+// errorArray is the errors array of Logger.Err
+type errorArray []errorInfo
+
+// MarshalLogArray implements zapcore.ArrayMarshaler
+func (a errorArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range a {
+		if err := enc.AppendObject(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// frame and stackTrace mirror github.com/pkg/errors
+type frame uintptr
+
+type stackTrace []frame
+
+// stackError carries the stack trace where it was created
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	return &stackError{msg: msg, pcs: pcs[:runtime.Callers(2, pcs)]}
+}
+
+func (e *stackError) Error() string {
+	return e.msg
+}
+
+func (e *stackError) StackTrace() stackTrace {
+	trace := make(stackTrace, 0, len(e.pcs))
+	for _, pc := range e.pcs {
+		trace = append(trace, frame(pc))
+	}
+	return trace
+}
+
+func TestErrorLevel(t *testing.T) {
+	errNotFound := errors.New("not found")
+	opt := CommonLogOpt.WithSentinelLevel(errNotFound, zapcore.DebugLevel).WithSentinelLevel(io.EOF, zapcore.WarnLevel)
+	logger := &Logger{opt: &opt}
+
+	testcases := []struct {
+		Err  error
+		Want zapcore.Level
+	}{
+		{errors.New("boom"), zapcore.ErrorLevel},
+		{context.Canceled, zapcore.InfoLevel},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), zapcore.WarnLevel},
+		{fmt.Errorf("get: %w", errNotFound), zapcore.DebugLevel},
+		{errors.Join(context.Canceled, errNotFound), zapcore.InfoLevel},
+		{errors.Join(context.Canceled, errors.New("boom")), zapcore.ErrorLevel},
+		{fmt.Errorf("read: %w", io.EOF), zapcore.WarnLevel},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, testcase.Want, logger.errorLevel(testcase.Err), testcase.Err.Error())
+	}
+}
+
+func TestErr(t *testing.T) {
+	logger := newTestLogger(t, "err", CommonLogOpt.WithTraceIDEnable(false))
+	ctx := context.Background()
+
+	stackErr := newStackError("disk full")
+	logger.Err(ctx, fmt.Errorf("save: %w", errors.Join(stackErr, context.Canceled)), "save failed", zap.Int("id", 1))
+	logger.Err(ctx, fmt.Errorf("request: %w", context.Canceled), "request canceled")
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0]["stack"], "TestErr")
+	errs := lines[0]["errors"].([]any)
+	assert.True(t, strings.HasPrefix(errs[2].(map[string]any)["stack"].(string),
+		"github.com/onesaltedseafish/go-utils/log.TestErr\n"))
+	delete(lines[0], "stack")
+	delete(errs[2].(map[string]any), "stack")
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "save failed", "name": "err", "id": float64(1), "error": "save: disk full\ncontext canceled", "errors": []any{
+			map[string]any{"message": "save: disk full\ncontext canceled", "type": "*fmt.wrapError"},
+			map[string]any{"message": "disk full\ncontext canceled", "type": "*errors.joinError"},
+			map[string]any{"message": "disk full", "type": "*log.stackError"},
+			map[string]any{"message": "context canceled", "type": "*errors.errorString"},
+		}},
+		{"msg": "request canceled", "name": "err", "error": "request: context canceled", "errors": []any{
+			map[string]any{"message": "request: context canceled", "type": "*fmt.wrapError"},
+			map[string]any{"message": "context canceled", "type": "*errors.errorString"},
+		}},
+	}, lines)
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ctxFieldsKey = logCtxFieldsKey{}
+)
+
+type logCtxFieldsKey struct{}
+
+// WithFields wrap ctx with fields attached to every log line written with it,
+// fields are merged with those already in ctx and a field replaces an earlier one with the same key
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := GetFieldsWithCtx(ctx)
+	merged := make([]zap.Field, 0, len(parent)+len(fields))
+	for _, field := range parent {
+		if !containsKey(fields, field.Key) {
+			merged = append(merged, field)
+		}
+	}
+	for i, field := range fields {
+		if !containsKey(fields[i+1:], field.Key) {
+			merged = append(merged, field)
+		}
+	}
+	return context.WithValue(ctx, ctxFieldsKey, merged)
+}
+
+// GetFieldsWithCtx get fields from ctx
+func GetFieldsWithCtx(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(ctxFieldsKey).([]zap.Field)
+	return fields
+}
+
+func containsKey(fields []zap.Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
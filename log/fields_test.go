@@ -0,0 +1,44 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), zap.String("user", "1"), zap.String("tenant", "a"))
+	nested := WithFields(ctx, zap.String("path", "/"), zap.String("user", "2"))
+
+	assert.Equal(t, []zap.Field{zap.String("user", "1"), zap.String("tenant", "a")}, GetFieldsWithCtx(ctx))
+	assert.Equal(t, []zap.Field{zap.String("tenant", "a"), zap.String("path", "/"), zap.String("user", "2")},
+		GetFieldsWithCtx(nested))
+	assert.Nil(t, GetFieldsWithCtx(context.Background()))
+}
+
+func TestLogWithNilCtx(t *testing.T) {
+	hasCtx := func(ctx context.Context, _ *Entry) bool {
+		return ctx != nil
+	}
+	logger := newTestLogger(t, "nilctx", CommonLogOpt.WithTraceIDEnable(true).WithHooks(hasCtx))
+	var ctx context.Context
+
+	logger.Info(ctx, "nil ctx")
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "nil ctx", "name": "nilctx", "trace_id": ""},
+	}, readLogLines(t, logger.opt.GetLogFilePath()))
+}
+
+func TestLogWithCtxFields(t *testing.T) {
+	logger := newTestLogger(t, "ctxfields", CommonLogOpt.WithTraceIDEnable(false))
+
+	ctx := WithFields(context.Background(), zap.String("user", "1"))
+	logger.Info(ctx, "with ctx fields", zap.Int("status", 200))
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "with ctx fields", "name": "ctxfields", "user": "1", "status": float64(200)},
+	}, readLogLines(t, logger.opt.GetLogFilePath()))
+}
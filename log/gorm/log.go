@@ -14,21 +14,23 @@ import (
 
 // Logger logger for gorm
 type Logger struct {
-	level zapcore.Level
+	level zapcore.Level // minimum level of this gorm logger, the shared logger level also applies
 	log   *log.Logger
 }
 
-// NewLogger new logger
+// NewLogger new logger, entries are only filtered by the shared logger level until LogMode is called
 func NewLogger(name string, opt *log.LoggerOpt) *Logger {
-	l := Logger{}
+	l := Logger{level: zapcore.DebugLevel}
 	l.log = log.GetLogger(name, opt)
 	return &l
 }
 
-// LogMode log mode
+// LogMode returns a copy logging at level, the shared logger and its level are not changed
 func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
 	newLogger := *l
 	switch level {
+	case logger.Silent:
+		newLogger.level = zapcore.InvalidLevel // above every level, nothing is logged
 	case logger.Info:
 		newLogger.level = zapcore.InfoLevel
 	case logger.Warn:
@@ -38,27 +40,38 @@ func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
 	default:
 		newLogger.level = zapcore.DebugLevel
 	}
-	newLogger.log.SetLevel(newLogger.level)
 	return &newLogger
 }
 
 // Info print info
 func (l *Logger) Info(ctx context.Context, msg string, _ ...interface{}) {
+	if l.level > zapcore.InfoLevel {
+		return
+	}
 	l.log.Info(ctx, msg)
 }
 
 // Warn print warn messages
 func (l *Logger) Warn(ctx context.Context, msg string, _ ...interface{}) {
+	if l.level > zapcore.WarnLevel {
+		return
+	}
 	l.log.Warn(ctx, msg)
 }
 
 // Error print error messages
 func (l *Logger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level > zapcore.ErrorLevel {
+		return
+	}
 	l.log.Warn(ctx, msg)
 }
 
 // Trace print sql message
 func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level > zapcore.DebugLevel {
+		return
+	}
 	elapsed := time.Since(begin)
 	sql, rows := fc()
 	l.log.Debug(
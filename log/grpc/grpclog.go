@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"google.golang.org/grpc/grpclog"
+)
+
+// Logger is a grpclog.DepthLoggerV2 writing to a log.Logger
+type Logger struct {
+	logger    *log.Logger
+	verbosity int
+}
+
+var _ grpclog.DepthLoggerV2 = (*Logger)(nil)
+
+// NewLogger new a grpc logger writing to logger,
+// V(l) reports true when l is not greater than verbosity
+func NewLogger(logger *log.Logger, verbosity int) *Logger {
+	// skip the logger method and the grpclog function calling it
+	return &Logger{logger: logger.WithCallerSkip(2), verbosity: verbosity}
+}
+
+// SetLogger routes grpc internal logs to logger
+func SetLogger(logger *log.Logger, verbosity int) {
+	grpclog.SetLoggerV2(NewLogger(logger, verbosity))
+}
+
+// Info implements grpclog.LoggerV2
+func (l *Logger) Info(args ...any) {
+	l.logger.Info(context.Background(), fmt.Sprint(args...))
+}
+
+// Infoln implements grpclog.LoggerV2
+func (l *Logger) Infoln(args ...any) {
+	l.logger.Info(context.Background(), sprintln(args))
+}
+
+// Infof implements grpclog.LoggerV2
+func (l *Logger) Infof(format string, args ...any) {
+	l.logger.Info(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Warning implements grpclog.LoggerV2
+func (l *Logger) Warning(args ...any) {
+	l.logger.Warn(context.Background(), fmt.Sprint(args...))
+}
+
+// Warningln implements grpclog.LoggerV2
+func (l *Logger) Warningln(args ...any) {
+	l.logger.Warn(context.Background(), sprintln(args))
+}
+
+// Warningf implements grpclog.LoggerV2
+func (l *Logger) Warningf(format string, args ...any) {
+	l.logger.Warn(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Error implements grpclog.LoggerV2
+func (l *Logger) Error(args ...any) {
+	l.logger.Error(context.Background(), fmt.Sprint(args...))
+}
+
+// Errorln implements grpclog.LoggerV2
+func (l *Logger) Errorln(args ...any) {
+	l.logger.Error(context.Background(), sprintln(args))
+}
+
+// Errorf implements grpclog.LoggerV2
+func (l *Logger) Errorf(format string, args ...any) {
+	l.logger.Error(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Fatal implements grpclog.LoggerV2, the logger exits after fatal entries are written
+func (l *Logger) Fatal(args ...any) {
+	l.logger.Fatal(context.Background(), fmt.Sprint(args...))
+}
+
+// Fatalln implements grpclog.LoggerV2
+func (l *Logger) Fatalln(args ...any) {
+	l.logger.Fatal(context.Background(), sprintln(args))
+}
+
+// Fatalf implements grpclog.LoggerV2
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.logger.Fatal(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// V implements grpclog.LoggerV2
+func (l *Logger) V(level int) bool {
+	return level <= l.verbosity
+}
+
+// InfoDepth implements grpclog.DepthLoggerV2
+func (l *Logger) InfoDepth(depth int, args ...any) {
+	l.logger.WithCallerSkip(depth).Info(context.Background(), sprintln(args))
+}
+
+// WarningDepth implements grpclog.DepthLoggerV2
+func (l *Logger) WarningDepth(depth int, args ...any) {
+	l.logger.WithCallerSkip(depth).Warn(context.Background(), sprintln(args))
+}
+
+// ErrorDepth implements grpclog.DepthLoggerV2
+func (l *Logger) ErrorDepth(depth int, args ...any) {
+	l.logger.WithCallerSkip(depth).Error(context.Background(), sprintln(args))
+}
+
+// FatalDepth implements grpclog.DepthLoggerV2
+func (l *Logger) FatalDepth(depth int, args ...any) {
+	l.logger.WithCallerSkip(depth).Fatal(context.Background(), sprintln(args))
+}
+
+// sprintln formats args as fmt.Sprintln without the trailing newline
+func sprintln(args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestLogger(t *testing.T) {
+	defer func() { _ = log.Shutdown(context.Background()) }()
+	dir := t.TempDir()
+	opt := log.CommonLogOpt.WithDirectory(dir).WithConsoleLog(false).WithTraceIDEnable(false)
+	opt.IsDefault = false
+	logger := log.GetLogger("grpclog", &opt)
+	SetLogger(logger, 1)
+	defer grpclog.SetLoggerV2(grpclog.NewLoggerV2(os.Stderr, os.Stderr, os.Stderr))
+
+	grpclog.Infof("info %d", 1)
+	grpclog.Component("transport").Warning("warning", 2)
+	assert.True(t, grpclog.V(1))
+	assert.False(t, grpclog.V(2))
+
+	content, err := os.ReadFile(filepath.Join(dir, "grpclog.log"))
+	assert.Nil(t, err)
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		entry := make(map[string]any)
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "info 1", entries[0]["msg"])
+	assert.Equal(t, "[transport] warning 2", entries[1]["msg"])
+	for _, entry := range entries {
+		assert.Contains(t, entry["caller"], "grpc/grpclog_test.go:")
+	}
+}
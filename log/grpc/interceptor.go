@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor extracts trace id from incoming metadata,
+// or creates one with log.NewTraceIDWithCtx, and logs every rpc through logger
+func UnaryServerInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		begin := time.Now()
+		ctx = serverContext(ctx)
+		resp, err := handler(ctx, req)
+		logRPC(ctx, logger, "grpc server request", info.FullMethod, begin, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor extracts trace id from incoming metadata,
+// or creates one with log.NewTraceIDWithCtx, and logs every stream through logger
+func StreamServerInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		begin := time.Now()
+		ctx := serverContext(ss.Context())
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logRPC(ctx, logger, "grpc server stream", info.FullMethod, begin, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor injects trace id of ctx into outgoing metadata and logs every rpc through logger
+func UnaryClientInterceptor(logger *log.Logger) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		begin := time.Now()
+		err := invoker(InjectTraceID(ctx), method, req, reply, cc, opts...)
+		logRPC(ctx, logger, "grpc client request", method, begin, err, zap.String("target", cc.Target()))
+		return err
+	}
+}
+
+// StreamClientInterceptor injects trace id of ctx into outgoing metadata and logs every stream through logger
+// once it ends, a stream ends when RecvMsg returns an error, io.EOF included,
+// or the reply of a stream without server streaming is received
+func StreamClientInterceptor(logger *log.Logger) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		begin := time.Now()
+		cs, err := streamer(InjectTraceID(ctx), desc, cc, method, opts...)
+		end := func(err error) {
+			logRPC(ctx, logger, "grpc client stream", method, begin, err, zap.String("target", cc.Target()))
+		}
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams, end: end}, nil
+	}
+}
+
+// clientStream logs a grpc.ClientStream once it ends
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	end           func(err error)
+	once          sync.Once
+}
+
+// RecvMsg implements grpc.ClientStream
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.once.Do(func() { s.end(nil) })
+	case err != nil || !s.serverStreams:
+		s.once.Do(func() { s.end(err) })
+	}
+	return err
+}
+
+// serverStream overrides context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func serverContext(ctx context.Context) context.Context {
+	ctx = ExtractTraceID(ctx)
+	if log.GetTraceIDWithCtx(ctx) == "" {
+		ctx = log.NewTraceIDWithCtx(ctx)
+	}
+	return ctx
+}
+
+func logRPC(
+	ctx context.Context,
+	logger *log.Logger,
+	msg string,
+	method string,
+	begin time.Time,
+	err error,
+	fields ...zap.Field,
+) {
+	code := status.Code(err)
+	fields = append(fields,
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(begin)),
+	)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	switch codeLevel(code) {
+	case zapcore.InfoLevel:
+		logger.Info(ctx, msg, fields...)
+	case zapcore.WarnLevel:
+		logger.Warn(ctx, msg, fields...)
+	default:
+		logger.Error(ctx, msg, fields...)
+	}
+}
+
+// codeLevel maps grpc codes to log levels, server side failures are errors
+func codeLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.NotFound, codes.AlreadyExists:
+		return zapcore.InfoLevel
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.WarnLevel
+	}
+}
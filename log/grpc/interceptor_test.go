@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestInterceptors(t *testing.T) {
+	opt := log.CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false)
+	opt.IsDefault = false
+	logger := log.GetLogger("grpc", &opt)
+	defer func() { _ = log.Shutdown(context.Background()) }()
+
+	var serverTraceIDs []string
+	recordTraceID := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		serverTraceIDs = append(serverTraceIDs, log.GetTraceIDWithCtx(ctx))
+		return handler(ctx, req)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(logger), recordTraceID),
+		grpc.StreamInterceptor(StreamServerInterceptor(logger)),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(logger)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(logger)),
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := log.WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "not-exist"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverTraceIDs[0])
+	assert.NotEmpty(t, serverTraceIDs[1])
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+	// the client stream is logged once its end is received
+	cancel()
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+	// graceful stop waits the stream handler to return
+	conn.Close()
+	server.GracefulStop()
+
+	content, err := os.ReadFile(filepath.Join(opt.Directory, "grpc.log"))
+	assert.Nil(t, err)
+	codes := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := make(map[string]any)
+		assert.Nil(t, json.Unmarshal([]byte(line), &fields))
+		msg := fields["msg"].(string)
+		codes[msg] = append(codes[msg], fmt.Sprintf("%s %s", fields["method"], fields["code"]))
+	}
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Check OK", "/grpc.health.v1.Health/Check NotFound"},
+		codes["grpc server request"])
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Check OK", "/grpc.health.v1.Health/Check NotFound"},
+		codes["grpc client request"])
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Watch Canceled"}, codes["grpc client stream"])
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Watch Canceled"}, codes["grpc server stream"])
+}
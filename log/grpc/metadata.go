@@ -0,0 +1,56 @@
+// Package grpc gRPC helpers for log
+package grpc
+
+import (
+	"context"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts metadata.MD to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+// Get implements propagation.TextMapCarrier
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set implements propagation.TextMapCarrier
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys implements propagation.TextMapCarrier
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// InjectTraceID add trace id of ctx to the outgoing metadata of ctx
+func InjectTraceID(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	log.InjectTraceID(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// ExtractTraceID wrap ctx with trace id found in the incoming metadata of ctx
+func ExtractTraceID(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return log.ExtractTraceID(ctx, metadataCarrier(md))
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestInjectExtractTraceID(t *testing.T) {
+	for _, traceID := range []string{"1234", "4bf92f3577b34da6a3ce929d0e0e4736"} {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "k", "v")
+		ctx = InjectTraceID(log.WithTraceID(ctx, traceID))
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"v"}, md.Get("k"))
+
+		// metadata received by server
+		serverCtx := ExtractTraceID(metadata.NewIncomingContext(context.Background(), md))
+		assert.Equal(t, traceID, log.GetTraceIDWithCtx(serverCtx))
+	}
+
+	assert.Equal(t, "", log.GetTraceIDWithCtx(ExtractTraceID(context.Background())))
+}
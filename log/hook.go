@@ -0,0 +1,38 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a log entry passed to hooks
+type Entry struct {
+	Level   zapcore.Level // the entry is written at the changed level when the logger level enables it
+	Message string
+	Fields  []zap.Field // trace, ctx and call fields before redaction, meta fields of With are not included
+}
+
+// Hook is called for every entry enabled by the logger level before it reaches the zap cores,
+// it may change the level, message and fields of entry and returns false to drop the entry.
+// The entry is redacted after all hooks ran, so fields added by hooks are masked too
+type Hook func(ctx context.Context, entry *Entry) bool
+
+// WithHooks new a child logger running hooks after the hooks of l,
+// the child shares sinks and level with l and l itself is not changed
+func (l *Logger) WithHooks(hooks ...Hook) *Logger {
+	child := NewFromLogger(l)
+	child.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], hooks...)
+	return child
+}
+
+// runHooks runs hooks in order, stopping at the first hook dropping the entry
+func runHooks(ctx context.Context, hooks []Hook, entry *Entry) bool {
+	for _, hook := range hooks {
+		if !hook(ctx, entry) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHooks(t *testing.T) {
+	var alerts []string
+	addHost := func(_ context.Context, entry *Entry) bool {
+		entry.Fields = append(entry.Fields, zap.String("host", "pod-1"), zap.String("owner", "ops@b.com"))
+		return true
+	}
+	dropHealthCheck := func(_ context.Context, entry *Entry) bool {
+		return entry.Message != "health check"
+	}
+	alert := func(ctx context.Context, entry *Entry) bool {
+		if entry.Level >= zapcore.ErrorLevel {
+			alerts = append(alerts, GetTraceIDWithCtx(ctx)+" "+entry.Message)
+		}
+		return true
+	}
+	metrics := NewMetrics()
+	base := newTestLogger(t, "hooks", CommonLogOpt.WithTraceIDEnable(false).
+		WithRedaction(RedactOpt{Patterns: []*regexp.Regexp{EmailPattern}}).
+		WithMetrics(metrics).
+		WithHooks(dropHealthCheck, addHost))
+	logger := base.WithHooks(alert)
+	ctx := WithTraceID(context.Background(), "trace")
+
+	logger.Info(ctx, "health check")
+	logger.Info(ctx, "mail a@b.com")
+	logger.Error(ctx, "failed")
+	base.Error(ctx, "no alert hook")
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	for _, line := range lines {
+		delete(line, "stack")
+	}
+	assert.Equal(t, []map[string]any{
+		{"msg": "mail ******", "name": "hooks", "host": "pod-1", "owner": "******"},
+		{"msg": "failed", "name": "hooks", "host": "pod-1", "owner": "******"},
+		{"msg": "no alert hook", "name": "hooks", "host": "pod-1", "owner": "******"},
+	}, lines)
+	assert.Equal(t, []string{"trace failed"}, alerts)
+	assert.Equal(t, uint64(1), metrics.Snapshot()[0].Entries[zapcore.InfoLevel], "dropped entries are not counted")
+}
+
+func TestHookLevel(t *testing.T) {
+	// canceled requests are not errors, noisy entries are moved below the logger level
+	downgrade := func(_ context.Context, entry *Entry) bool {
+		switch entry.Message {
+		case "canceled":
+			entry.Level = zapcore.InfoLevel
+		case "noisy":
+			entry.Level = zapcore.DebugLevel
+		}
+		return true
+	}
+	metrics := NewMetrics()
+	logger := newTestLogger(t, "hooklevel", CommonLogOpt.WithTraceIDEnable(false).
+		WithLogLevel(zapcore.InfoLevel).WithMetrics(metrics).WithHooks(downgrade))
+	ctx := context.Background()
+
+	logger.Error(ctx, "canceled")
+	logger.Warn(ctx, "noisy")
+	logger.Err(ctx, errors.New("failed"), "canceled")
+
+	content, err := os.ReadFile(logger.opt.GetLogFilePath())
+	assert.Nil(t, err)
+	var levels, callers []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var fields struct {
+			Level  string `json:"level"`
+			Caller string `json:"caller"`
+			Stack  string `json:"stack"`
+		}
+		assert.Nil(t, json.Unmarshal([]byte(line), &fields))
+		assert.Empty(t, fields.Stack, "stack traces follow the level the entry is written at")
+		levels = append(levels, fields.Level)
+		file := fields.Caller[strings.LastIndex(fields.Caller, "/")+1:]
+		callers = append(callers, file[:strings.LastIndex(file, ":")])
+	}
+	assert.Equal(t, []string{"info", "info"}, levels)
+	assert.Equal(t, []string{"hook_test.go", "hook_test.go"}, callers)
+	assert.Equal(t, uint64(2), metrics.Snapshot()[0].Entries[zapcore.InfoLevel])
+	assert.Equal(t, uint64(0), metrics.Snapshot()[0].Entries[zapcore.ErrorLevel])
+}
+
+func TestHooksWithSlogGroup(t *testing.T) {
+	addHost := func(_ context.Context, entry *Entry) bool {
+		entry.Fields = append(entry.Fields, zap.String("host", "pod-1"))
+		return true
+	}
+	logger := newTestLogger(t, "hookslog", CommonLogOpt.WithTraceIDEnable(false).WithHooks(addHost))
+
+	slog.New(NewSlogHandler(logger)).WithGroup("req").With("a", 1).Info("grouped", "b", 2)
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "grouped", "name": "hookslog", "host": "pod-1", "req": map[string]any{"a": float64(1), "b": float64(2)}},
+	}, readLogLines(t, logger.opt.GetLogFilePath()))
+}
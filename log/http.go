@@ -0,0 +1,136 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTPMiddleware reads trace id from traceparent or X-Request-ID of incoming requests,
+// or creates one with NewTraceIDWithCtx, echoes it in X-Request-ID of the response
+// and writes one access log line per request through logger
+func HTTPMiddleware(logger *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			begin := time.Now()
+			ctx := ExtractTraceIDHTTP(r.Context(), r.Header)
+			if GetTraceIDWithCtx(ctx) == "" {
+				ctx = NewTraceIDWithCtx(ctx)
+			}
+			w.Header().Set(RequestIDHeader, GetTraceIDWithCtx(ctx))
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			level := zapcore.InfoLevel
+			if rw.status >= http.StatusInternalServerError {
+				level = zapcore.WarnLevel
+			}
+			logger.logAt(ctx, level, "http request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", rw.status),
+				zap.Int64("bytes", rw.bytes),
+				zap.Duration("latency", time.Since(begin)),
+			)
+		})
+	}
+}
+
+// responseWriter records status and size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, a hijacked response is logged with status 101
+// unless the handler wrote another status before
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Transport http.RoundTripper propagating trace id of request context
+// with traceparent and X-Request-ID headers
+type Transport struct {
+	Base   http.RoundTripper // http.DefaultTransport when nil
+	Logger *Logger           // when not nil, log every request
+}
+
+// NewTransport new a transport wrapping base
+func NewTransport(base http.RoundTripper, logger *Logger) *Transport {
+	return &Transport{Base: base, Logger: logger}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := r.Context()
+	// RoundTripper must not modify the original request
+	r = r.Clone(ctx)
+	InjectTraceIDHTTP(ctx, r.Header)
+
+	begin := time.Now()
+	resp, err := base.RoundTrip(r)
+	if t.Logger == nil {
+		return resp, err
+	}
+	fields := []zap.Field{
+		zap.String("method", r.Method),
+		zap.String("url", r.URL.Redacted()),
+		zap.Duration("latency", time.Since(begin)),
+	}
+	if err != nil {
+		t.Logger.Error(ctx, "http client request", append(fields, zap.Error(err))...)
+		return resp, err
+	}
+	t.Logger.Info(ctx, "http client request", append(fields, zap.Int("status", resp.StatusCode))...)
+	return resp, err
+}
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	logger := newTestLogger(t, "http", CommonLogOpt)
+
+	var handlerTraceID string
+	server := httptest.NewServer(HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerTraceID = GetTraceIDWithCtx(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	})))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, logger)}
+	testcases := []struct {
+		ctx context.Context
+	}{
+		{WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")},
+		{WithTraceID(context.Background(), "1234")},
+		{context.Background()},
+	}
+	for _, testcase := range testcases {
+		req, _ := http.NewRequestWithContext(testcase.ctx, http.MethodGet, server.URL+"/hello", nil)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		assert.Equal(t, handlerTraceID, resp.Header.Get(RequestIDHeader))
+		assert.NotEmpty(t, handlerTraceID)
+		if traceID := GetTraceIDWithCtx(testcase.ctx); traceID != "" {
+			assert.Equal(t, traceID, handlerTraceID)
+		}
+		assert.Empty(t, req.Header, "request of caller must not be modified")
+	}
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 2*len(testcases), len(lines))
+	for _, line := range lines {
+		assert.Equal(t, "GET", line["method"])
+		assert.Equal(t, float64(http.StatusTeapot), line["status"])
+		if line["msg"] == "http request" {
+			assert.Equal(t, "/hello", line["path"])
+			assert.Equal(t, float64(5), line["bytes"])
+		}
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	logger := newTestLogger(t, "httphijack", CommonLogOpt)
+
+	server := httptest.NewServer(HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		_ = rw.Flush()
+	})))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/upgrade", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "test")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	// the request is logged once the handler returns, after the client got the response
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(logger.opt.GetLogFilePath())
+		return err == nil && len(content) > 0
+	}, time.Second, 10*time.Millisecond)
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 1, len(lines))
+	assert.Equal(t, "/upgrade", lines[0]["path"])
+	assert.Equal(t, float64(http.StatusSwitchingProtocols), lines[0]["status"])
+}
@@ -3,12 +3,16 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/natefinch/lumberjack"
 	"go.opentelemetry.io/contrib/bridges/otelzap"
 	otellog "go.opentelemetry.io/otel/sdk/log"
 	"go.uber.org/zap"
@@ -23,16 +27,17 @@ var (
 
 var (
 	defaultLogOpt = LoggerOpt{
-		LogLevel:         zapcore.InfoLevel,
-		Directory:        ".",
-		TraceIDEnable:    true,
-		MaxSize:          15,
-		MaxBackups:       5,
-		MaxAge:           365,
-		IsDefault:        true,
-		ConsoleLogEnable: true,
-		EnableCaller:     true,
-		LoggerProvider:   nil,
+		LogLevel:           zapcore.InfoLevel,
+		Directory:          ".",
+		TraceIDEnable:      true,
+		MaxSize:            15,
+		MaxBackups:         5,
+		MaxAge:             365,
+		IsDefault:          true,
+		ConsoleLogEnable:   true,
+		EnableCaller:       true,
+		LoggerProvider:     nil,
+		DropReportInterval: time.Minute,
 	}
 
 	// CommonLogOpt use to easliy construct custom log options
@@ -50,6 +55,18 @@ var (
 		EncodeCaller:  zapcore.FullCallerEncoder,      // Full caller path
 	}
 
+	logLogfmtEncodeCfg = zapcore.EncoderConfig{
+		MessageKey:    "msg",                         // default msg key
+		LevelKey:      "level",                       // log level key
+		CallerKey:     "caller",                      // caller key
+		TimeKey:       "time",                        // log time key
+		StacktraceKey: "stack",                       // stack trace key
+		LineEnding:    zapcore.DefaultLineEnding,     // log ends with "\n"
+		EncodeLevel:   zapcore.LowercaseLevelEncoder, // log level format "info"
+		EncodeTime:    zapcore.RFC3339TimeEncoder,    // not precise time
+		EncodeCaller:  zapcore.ShortCallerEncoder,    // short caller path
+	}
+
 	logConsoleEncodeCfg = zapcore.EncoderConfig{
 		MessageKey:    "msg",                            // default msg key
 		LevelKey:      "level",                          // log level key
@@ -63,63 +80,116 @@ var (
 	}
 )
 
-func newZapLogger(opt *LoggerOpt) *zap.Logger {
-	// create log file folder
-	if err := opt.CreateDirectory(); err != nil {
-		panic(err)
-	}
-
-	w := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   opt.GetLogFilePath(),
-		MaxSize:    opt.MaxSize,
-		MaxBackups: opt.MaxBackups,
-		MaxAge:     opt.MaxAge,
-	})
-
+// newZapCore new the cores of a logger, w is nil when log file is disabled
+// and errW is nil when error log file is disabled
+func newZapCore(
+	opt *LoggerOpt, level zapcore.LevelEnabler, w zapcore.WriteSyncer, errW zapcore.WriteSyncer,
+) zapcore.Core {
 	cores := make([]zapcore.Core, 0)
-	opts := make([]zap.Option, 0)
 
-	jsonCore := zapcore.NewCore(zapcore.NewJSONEncoder(logJsonEncodeCfg), w, zap.DebugLevel)
-	consoleCore := zapcore.NewCore(zapcore.NewConsoleEncoder(logConsoleEncodeCfg), zapcore.Lock(os.Stdout), zap.DebugLevel)
-
-	cores = append(cores, jsonCore)
+	fileEncoding := opt.FileEncoding
+	if fileEncoding == "" {
+		fileEncoding = EncodingJSON
+	}
+	if w != nil {
+		fileCore := zapcore.NewCore(newEncoder(fileEncoding, opt, false), w, minLevel(level, opt.FileLevel))
+		cores = append(cores, fileCore)
+	}
+	if errW != nil {
+		errorLevel := zapcore.ErrorLevel
+		errorCore := zapcore.NewCore(newEncoder(fileEncoding, opt, false), errW, minLevel(level, &errorLevel))
+		cores = append(cores, errorCore)
+	}
 	if opt.ConsoleLogEnable {
+		consoleEncoding := opt.ConsoleEncoding
+		if consoleEncoding == "" {
+			consoleEncoding = EncodingConsole
+		}
+		consoleCore := zapcore.NewCore(
+			newEncoder(consoleEncoding, opt, useColor(opt.ConsoleColor, os.Stdout)),
+			zapcore.Lock(consoleWriter{os.Stdout}),
+			minLevel(level, opt.ConsoleLevel),
+		)
 		cores = append(cores, consoleCore)
 	}
+	for _, sink := range opt.Sinks {
+		cores = append(cores, sink.newCore(opt, level))
+	}
 
 	if opt.LoggerProvider != nil { // support OTLP
 		otelzapCore := otelzap.NewCore(
 			opt.Name,
 			otelzap.WithLoggerProvider(opt.LoggerProvider),
 		)
-		cores = append(cores, otelzapCore)
+		cores = append(cores, newLevelCore(otelzapCore, level))
 	}
 
-	opts = append(opts, zap.AddStacktrace(zap.ErrorLevel))
+	return zapcore.NewTee(cores...)
+}
+
+func newZapLogger(opt *LoggerOpt, core zapcore.Core) *zap.Logger {
+	opts := make([]zap.Option, 0)
+	var onFatal zapcore.CheckWriteHook = fatalHook{}
+	if opt.FatalHook != nil {
+		onFatal = opt.FatalHook
+	}
+	opts = append(opts, zap.AddStacktrace(zap.ErrorLevel), zap.WithFatalHook(onFatal))
 	if opt.EnableCaller {
 		opts = append(opts, zap.AddCallerSkip(2))
 		opts = append(opts, zap.AddCaller())
 	}
 
-	return zap.New(
-		zapcore.NewTee(
-			cores...,
-		),
-		opts...,
-	)
+	return zap.New(core, opts...)
 }
 
 // newLogger return a well-configed logger
 func newLogger(name string, opt *LoggerOpt) *Logger {
-	opt.Name = fmt.Sprintf("%s.log", name)
+	// every logger owns a copy of its options
+	// so changing one logger never affects others created from the same options
+	o := *opt
+	o.Name = fmt.Sprintf("%s.log", name)
 	logger := &Logger{
-		opt: opt,
+		name:   name,
+		opt:    &o,
+		level:  zap.NewAtomicLevelAt(o.LogLevel),
+		drops:  newDropCounter(o.DropReportInterval),
+		redact: newRedactor(o.Redact),
+		counts: o.Metrics.counts(name),
+		hooks:  o.Hooks,
+		closed: new(atomic.Bool),
+	}
+	var w zapcore.WriteSyncer
+	if !o.FileLogDisable {
+		// create log file folder
+		if err := o.CreateDirectory(); err != nil {
+			panic(err)
+		}
+		logger.file = newFileWriter(o.GetLogFilePath(), &o)
+		w = logger.file
+		if o.Async != nil {
+			logger.async = newAsyncWriter(logger.file, *o.Async, logger.drops)
+			w = logger.async
+		}
 	}
-	logger.zaplog = newZapLogger(logger.opt)
-	logger.zaplog = logger.zaplog.With(zap.String("name", name))
-	if opt.IsDefault {
-		defaultLogger = logger
+	var errW zapcore.WriteSyncer
+	if o.ErrorLog != nil {
+		if err := o.CreateDirectory(); err != nil {
+			panic(err)
+		}
+		// errors are rare and wanted most after a crash, so they are never buffered
+		errOpt := o.WithLogRetention(o.ErrorLog.MaxSize, o.ErrorLog.MaxBackups, o.ErrorLog.MaxAge)
+		logger.errorFile = newFileWriter(o.GetErrorLogFilePath(), &errOpt)
+		errW = logger.errorFile
 	}
+	core := newZapCore(logger.opt, logger.level, w, errW)
+	// drop reports bypass sampling, rate limiting and the logger level,
+	// levels of the destinations still apply
+	logger.drops.reporter = newZapCore(logger.opt, zapcore.DebugLevel, w, errW).
+		With([]zap.Field{zap.String("name", name)})
+	logger.drops.start()
+	o.Metrics.addDrops(name, logger.drops)
+	logger.base = newZapLogger(logger.opt, newLimitedCore(core, logger.opt, logger.drops))
+	logger.zaplog = logger.base.With(zap.String("name", name))
 	return logger
 }
 
@@ -137,9 +207,21 @@ func GetLogger(name string, opt *LoggerOpt) *Logger {
 	}
 	l = newLogger(name, opt)
 	loggers[name] = l
+	if l.opt.IsDefault {
+		defaultLogger = l
+	}
 	return l
 }
 
+// NewLogger new a logger that is not registered,
+// so GetLogger, Sync and Shutdown never see it and it never becomes the default logger
+func NewLogger(name string, opt *LoggerOpt) *Logger {
+	if opt == nil {
+		opt = &defaultLogOpt
+	}
+	return newLogger(name, opt)
+}
+
 // GetDefaultLogger get default logger
 // if no default logger was initilized ** nil ** will be return
 func GetDefaultLogger() *Logger {
@@ -148,23 +230,66 @@ func GetDefaultLogger() *Logger {
 
 // Logger self defined Logger
 type Logger struct {
-	zaplog *zap.Logger
-	opt    *LoggerOpt
+	name      string
+	base      *zap.Logger // zap logger without any fields, shared by all children
+	zaplog    *zap.Logger // base with name and meta fields
+	fields    []zap.Field // meta fields, kept to rebuild zaplog under a new name
+	opt       *LoggerOpt
+	level     zap.AtomicLevel // shared with the zap cores, opt.LogLevel only keeps the initial level
+	file      *fileWriter     // nil when log file is disabled
+	errorFile *fileWriter     // nil when error log file is disabled
+	async     *asyncWriter    // nil when log file is written synchronously
+	drops     *dropCounter
+	redact    *redactor    // nil when redaction is disabled
+	counts    *levelCounts // nil when metrics are disabled
+	hooks     []Hook
+	closed    *atomic.Bool // shared with children, entries are dropped once the logger is closed
+	derived   bool         // children do not own the outputs they share with the logger they derive from
 }
 
 // LoggerOpt configures the logger
 type LoggerOpt struct {
-	LogLevel         zapcore.Level
-	Directory        string                  // log file directory
-	Name             string                  // log file name
-	TraceIDEnable    bool                    // enable traceid field
-	MaxSize          int                     // Log File Max Size MB
-	MaxBackups       int                     // The number of backup log file
-	MaxAge           int                     // The days the log will be kept
-	IsDefault        bool                    // is defalut logger?
-	ConsoleLogEnable bool                    // enable console log?
-	EnableCaller     bool                    // enable Caller?
-	LoggerProvider   *otellog.LoggerProvider // when not nil, use otelzap bridge
+	LogLevel           zapcore.Level
+	Directory          string                  // log file directory
+	Name               string                  // log file name
+	TraceIDEnable      bool                    // enable traceid field
+	MaxSize            int                     // Log File Max Size MB
+	MaxBackups         int                     // The number of backup log file
+	MaxAge             int                     // The days the log will be kept
+	IsDefault          bool                    // is defalut logger?
+	ConsoleLogEnable   bool                    // enable console log?
+	EnableCaller       bool                    // enable Caller?
+	LoggerProvider     *otellog.LoggerProvider // when not nil, use otelzap bridge
+	BaggageKeys        []string                // baggage members logged when traceid enabled
+	Sampling           *SamplingOpt            // when not nil, sample repeated messages
+	RateLimit          int                     // max lines per second, 0 means no limit
+	DropReportInterval time.Duration           // interval to log dropped entries count, 0 disables the report
+	Async              *AsyncOpt               // when not nil, write log file asynchronously
+	FileLogDisable     bool                    // disable log file, e.g. when only sinks are wanted
+	Sinks              []Sink                  // extra log destinations
+	FileLevel          *zapcore.Level          // minimum level of log file, nil follows LogLevel only
+	ConsoleLevel       *zapcore.Level          // minimum level of console log, nil follows LogLevel only
+	FileEncoding       Encoding                // log file encoding, json when empty
+	ConsoleEncoding    Encoding                // console log encoding, console when empty
+	ConsoleColor       ColorMode               // color console log levels, auto detected when empty
+	TimeFormat         string                  // time layout, e.g. time.DateTime, empty keeps the encoding default
+	CallerFormat       CallerFormat            // caller format, empty keeps the encoding default
+	Redact             *RedactOpt              // when not nil, mask sensitive data in messages and fields
+	Rotation           RotationPeriod          // rotate log file hourly or daily besides rotating by size
+	Compress           bool                    // gzip rotated log files
+	LocalTime          bool                    // use local time instead of UTC for rotation periods and backup file names
+	ErrorLog           *ErrorLogOpt            // when not nil, also write error and fatal entries to <name>.error.log
+	Metrics            *Metrics                // when not nil, count entries by logger name and level
+	Hooks              []Hook                  // called in order for every entry before it is written
+	SentinelLevels     []SentinelLevel         // levels of errors logged by Err, nil uses DefaultSentinelLevels
+	FatalHook          zapcore.CheckWriteHook  // called after fatal entries are written, nil syncs all loggers and exits
+}
+
+// ErrorLogOpt configures retention of the error log file
+type ErrorLogOpt struct {
+	MaxSize    int // Error Log File Max Size MB
+	MaxBackups int // The number of backup error log file
+	MaxAge     int // The days the error log will be kept
 }
 
 // GetLogFilePath get log dst file path
@@ -176,6 +301,12 @@ func (opt LoggerOpt) GetLogFilePath() string {
 	return filepath.Join(absPath, opt.Name)
 }
 
+// GetErrorLogFilePath get error log file path, next to the log file
+func (opt LoggerOpt) GetErrorLogFilePath() string {
+	path := opt.GetLogFilePath()
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".error.log"
+}
+
 // CreateDirectory create logfile directory
 func (opt LoggerOpt) CreateDirectory() error {
 	if filepath.Clean(opt.Directory) == "" {
@@ -200,6 +331,99 @@ func (opt LoggerOpt) WithTraceIDEnable(enable bool) LoggerOpt {
 	return opt
 }
 
+// WithBaggageKeys setting baggage members logged as "baggage.<key>" fields
+func (opt LoggerOpt) WithBaggageKeys(keys ...string) LoggerOpt {
+	opt.BaggageKeys = keys
+	return opt
+}
+
+// WithSampling sample messages, for every message logs the first entries
+// in each tick then every thereafter-th entry, entries at error level and above are never sampled
+func (opt LoggerOpt) WithSampling(tick time.Duration, first int, thereafter int) LoggerOpt {
+	opt.Sampling = &SamplingOpt{Tick: tick, First: first, Thereafter: thereafter}
+	return opt
+}
+
+// WithRateLimit sets max lines per second, 0 means no limit,
+// entries at error level and above are never dropped
+func (opt LoggerOpt) WithRateLimit(linesPerSecond int) LoggerOpt {
+	opt.RateLimit = linesPerSecond
+	return opt
+}
+
+// WithDropReportInterval sets interval to log dropped entries count, 0 disables the report
+func (opt LoggerOpt) WithDropReportInterval(interval time.Duration) LoggerOpt {
+	opt.DropReportInterval = interval
+	return opt
+}
+
+// WithAsync write log file asynchronously through a buffer of bufferSize entries
+// flushed every flushInterval, policy decides what to do when the buffer is full
+func (opt LoggerOpt) WithAsync(bufferSize int, flushInterval time.Duration, policy OverflowPolicy) LoggerOpt {
+	opt.Async = &AsyncOpt{BufferSize: bufferSize, FlushInterval: flushInterval, Policy: policy}
+	return opt
+}
+
+// WithFileLog enable log file
+func (opt LoggerOpt) WithFileLog(enable bool) LoggerOpt {
+	opt.FileLogDisable = !enable
+	return opt
+}
+
+// WithSinks adds extra log destinations
+func (opt LoggerOpt) WithSinks(sinks ...Sink) LoggerOpt {
+	opt.Sinks = append(opt.Sinks[:len(opt.Sinks):len(opt.Sinks)], sinks...)
+	return opt
+}
+
+// WithFileLevel sets minimum level of log file on top of LogLevel
+func (opt LoggerOpt) WithFileLevel(level zapcore.Level) LoggerOpt {
+	opt.FileLevel = &level
+	return opt
+}
+
+// WithConsoleLevel sets minimum level of console log on top of LogLevel
+func (opt LoggerOpt) WithConsoleLevel(level zapcore.Level) LoggerOpt {
+	opt.ConsoleLevel = &level
+	return opt
+}
+
+// WithFileEncoding sets log file encoding
+func (opt LoggerOpt) WithFileEncoding(encoding Encoding) LoggerOpt {
+	opt.FileEncoding = encoding
+	return opt
+}
+
+// WithConsoleEncoding sets console log encoding
+func (opt LoggerOpt) WithConsoleEncoding(encoding Encoding) LoggerOpt {
+	opt.ConsoleEncoding = encoding
+	return opt
+}
+
+// WithConsoleColor sets whether console log levels are colored
+func (opt LoggerOpt) WithConsoleColor(mode ColorMode) LoggerOpt {
+	opt.ConsoleColor = mode
+	return opt
+}
+
+// WithTimeFormat sets time layout of every log destination
+func (opt LoggerOpt) WithTimeFormat(layout string) LoggerOpt {
+	opt.TimeFormat = layout
+	return opt
+}
+
+// WithCallerFormat sets caller format of every log destination
+func (opt LoggerOpt) WithCallerFormat(format CallerFormat) LoggerOpt {
+	opt.CallerFormat = format
+	return opt
+}
+
+// WithRedaction mask sensitive data in messages and fields of every log destination
+func (opt LoggerOpt) WithRedaction(redact RedactOpt) LoggerOpt {
+	opt.Redact = &redact
+	return opt
+}
+
 // WithLogRetention sets log retention
 func (opt LoggerOpt) WithLogRetention(maxSize int, maxBackups int, maxAge int) LoggerOpt {
 	opt.MaxAge = maxAge
@@ -208,6 +432,50 @@ func (opt LoggerOpt) WithLogRetention(maxSize int, maxBackups int, maxAge int) L
 	return opt
 }
 
+// WithLogRotation sets time based log rotation,
+// compress gzips rotated files and localTime uses local time instead of UTC in backup file names
+func (opt LoggerOpt) WithLogRotation(rotation RotationPeriod, compress bool, localTime bool) LoggerOpt {
+	opt.Rotation = rotation
+	opt.Compress = compress
+	opt.LocalTime = localTime
+	return opt
+}
+
+// WithErrorLog enables the error log file with its own retention
+func (opt LoggerOpt) WithErrorLog(maxSize int, maxBackups int, maxAge int) LoggerOpt {
+	opt.ErrorLog = &ErrorLogOpt{MaxSize: maxSize, MaxBackups: maxBackups, MaxAge: maxAge}
+	return opt
+}
+
+// WithMetrics counts entries of the logger and its children in m
+func (opt LoggerOpt) WithMetrics(m *Metrics) LoggerOpt {
+	opt.Metrics = m
+	return opt
+}
+
+// WithHooks adds hooks called in order for every entry before it is written
+func (opt LoggerOpt) WithHooks(hooks ...Hook) LoggerOpt {
+	opt.Hooks = append(opt.Hooks[:len(opt.Hooks):len(opt.Hooks)], hooks...)
+	return opt
+}
+
+// WithSentinelLevel logs errors matching err at level in Err, on top of DefaultSentinelLevels
+func (opt LoggerOpt) WithSentinelLevel(err error, level zapcore.Level) LoggerOpt {
+	levels := opt.SentinelLevels
+	if levels == nil {
+		levels = DefaultSentinelLevels
+	}
+	opt.SentinelLevels = append(levels[:len(levels):len(levels)], SentinelLevel{Err: err, Level: level})
+	return opt
+}
+
+// WithFatalHook sets hook called after fatal entries are written instead of exiting,
+// e.g. zapcore.WriteThenPanic in tests
+func (opt LoggerOpt) WithFatalHook(hook zapcore.CheckWriteHook) LoggerOpt {
+	opt.FatalHook = hook
+	return opt
+}
+
 // WithLogLevel sets log level
 func (opt LoggerOpt) WithLogLevel(level zapcore.Level) LoggerOpt {
 	opt.LogLevel = level
@@ -233,69 +501,293 @@ func (opt LoggerOpt) WithOtelLoggerProvider(provider *otellog.LoggerProvider) Lo
 
 // Debug the msg
 func (l *Logger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
-	l.log(ctx, zapcore.DebugLevel, l.zaplog.Debug, msg, fields...)
+	l.log(ctx, zapcore.DebugLevel, l.levelFunc, msg, fields...)
 }
 
 // Info the msg
 func (l *Logger) Info(ctx context.Context, msg string, fields ...zap.Field) {
-	l.log(ctx, zapcore.InfoLevel, l.zaplog.Info, msg, fields...)
+	l.log(ctx, zapcore.InfoLevel, l.levelFunc, msg, fields...)
 }
 
 // Warn the msg
 func (l *Logger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
-	l.log(ctx, zapcore.WarnLevel, l.zaplog.Warn, msg, fields...)
+	l.log(ctx, zapcore.WarnLevel, l.levelFunc, msg, fields...)
 }
 
 // Error the msg
 func (l *Logger) Error(ctx context.Context, msg string, fields ...zap.Field) {
-	l.log(ctx, zapcore.ErrorLevel, l.zaplog.Error, msg, fields...)
+	l.log(ctx, zapcore.ErrorLevel, l.levelFunc, msg, fields...)
 }
 
 // Fatal the msg and exit with errcode 1
 func (l *Logger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
-	l.log(ctx, zapcore.FatalLevel, l.zaplog.Fatal, msg, fields...)
+	l.log(ctx, zapcore.FatalLevel, l.levelFunc, msg, fields...)
+}
+
+// logAt logs the msg at a level decided at runtime
+func (l *Logger) logAt(ctx context.Context, level zapcore.Level, msg string, fields ...zap.Field) {
+	l.log(ctx, level, l.levelFunc, msg, fields...)
+}
+
+// levelFunc returns the zap method logging at level,
+// log calls the method directly to keep the caller skip
+func (l *Logger) levelFunc(level zapcore.Level) func(msg string, fields ...zap.Field) {
+	switch level {
+	case zapcore.DebugLevel:
+		return l.zaplog.Debug
+	case zapcore.WarnLevel:
+		return l.zaplog.Warn
+	case zapcore.ErrorLevel:
+		return l.zaplog.Error
+	case zapcore.FatalLevel:
+		return l.zaplog.Fatal
+	default:
+		return l.zaplog.Info
+	}
+}
+
+// callerFunc returns a levelFunc writing entries through Check with the caller at pc
+// and the time t instead of where the func is called and now, pc and t are ignored when zero.
+// Adapters pass it to log so callers of other logging packages are reported instead of the adapter
+func (l *Logger) callerFunc(pc uintptr, t time.Time) func(level zapcore.Level) func(msg string, fields ...zap.Field) {
+	return func(level zapcore.Level) func(msg string, fields ...zap.Field) {
+		return func(msg string, fields ...zap.Field) {
+			ce := l.zaplog.Check(level, msg)
+			if ce == nil {
+				return
+			}
+			if !t.IsZero() {
+				ce.Time = t
+			}
+			if ce.Caller.Defined && pc != 0 {
+				frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+				ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+				ce.Caller.Function = frame.Function
+			}
+			ce.Write(fields...)
+		}
+	}
 }
 
 func (l *Logger) log(
 	ctx context.Context,
 	logLevel zapcore.Level,
-	logFunc func(msg string, fields ...zap.Field),
+	levelFunc func(level zapcore.Level) func(msg string, fields ...zap.Field),
 	msg string,
 	fields ...zap.Field,
 ) {
-	if logLevel < l.opt.LogLevel {
+	if !l.level.Enabled(logLevel) || l.closed.Load() {
 		return
 	}
+	if ctx == nil { // accepted like before ctx fields were added
+		ctx = context.Background()
+	}
 	var dst []zapcore.Field
 	if l.opt.TraceIDEnable {
-		dst = append(dst, zap.String("trace_id", GetTraceIDWithCtx(ctx)))
+		dst = append(dst, getTraceFieldsWithCtx(ctx, l.opt.BaggageKeys)...)
 	}
+	// add fields carried by ctx
+	dst = append(dst, GetFieldsWithCtx(ctx)...)
 	// add remaining fields
 	dst = append(dst, fields...)
-	logFunc(msg, dst...)
+	if len(l.hooks) > 0 {
+		entry := Entry{Level: logLevel, Message: msg, Fields: dst}
+		if !runHooks(ctx, l.hooks, &entry) {
+			return
+		}
+		if entry.Level != logLevel && !l.level.Enabled(entry.Level) {
+			return
+		}
+		logLevel, msg, dst = entry.Level, entry.Message, entry.Fields
+	}
+	// redact after hooks so fields added by hooks are masked too
+	msg, dst = l.redact.message(msg), l.redact.fields(dst)
+	l.counts.add(logLevel)
+	levelFunc(logLevel)(msg, dst...)
 }
 
 // SetLevel setting log level
 func (l *Logger) SetLevel(level zapcore.Level) {
-	l.opt.LogLevel = level
+	l.level.SetLevel(level)
+}
+
+// Level get current log level
+func (l *Logger) Level() zapcore.Level {
+	return l.level.Level()
+}
+
+// Sync flushes buffered entries of the logger
+func (l *Logger) Sync() error {
+	return l.zaplog.Sync()
+}
+
+// Close flushes buffered entries, closes the log files and unregisters the logger,
+// entries logged after Close by the logger or its children are dropped.
+// Close of a child from With, Named, WithCallerSkip or NewFromLogger only flushes,
+// the logger it derives from owns the outputs
+func (l *Logger) Close() error {
+	if l.derived {
+		return l.Sync()
+	}
+	if !l.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	mu.Lock()
+	if loggers[l.name] == l {
+		delete(loggers, l.name)
+	}
+	if defaultLogger == l {
+		defaultLogger = nil
+	}
+	mu.Unlock()
+	l.drops.stop()
+	errs := []error{l.Sync()}
+	if l.async != nil {
+		errs = append(errs, l.async.Close())
+	}
+	if l.file != nil {
+		errs = append(errs, l.file.Close())
+	}
+	if l.errorFile != nil {
+		errs = append(errs, l.errorFile.Close())
+	}
+	for _, sink := range l.opt.Sinks {
+		errs = append(errs, sink.close())
+	}
+	return errors.Join(errs...)
+}
+
+// SetRetention changes log file retention of the logger
+func (l *Logger) SetRetention(maxSize int, maxBackups int, maxAge int) error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.setRetention(maxSize, maxBackups, maxAge)
 }
 
 // WithLoggerMetaFields set meta fields for logger
+//
+// Deprecated: it changes the logger in place, use With to get a child logger instead
 func (l *Logger) WithLoggerMetaFields(fields ...zapcore.Field) *Logger {
+	fields = l.redact.fields(fields)
 	l.zaplog = l.zaplog.With(fields...)
+	l.fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
 	return l
 }
 
+// With new a child logger with meta fields added,
+// the child shares sinks and level with l and l itself is not changed
+func (l *Logger) With(fields ...zapcore.Field) *Logger {
+	child := NewFromLogger(l)
+	fields = l.redact.fields(fields)
+	child.zaplog = l.zaplog.With(fields...)
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	return child
+}
+
+// Named new a child logger named "<name>.<sub>",
+// the child shares sinks and level with l and l itself is not changed
+func (l *Logger) Named(sub string) *Logger {
+	child := NewFromLogger(l)
+	child.name = fmt.Sprintf("%s.%s", l.name, sub)
+	child.counts = l.opt.Metrics.counts(child.name)
+	child.zaplog = l.base.With(zap.String("name", child.name)).With(l.fields...)
+	return child
+}
+
+// WithCallerSkip new a child logger reporting the caller skip frames further up the stack,
+// for wrappers of the logger that want their callers reported instead of themselves
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	child := NewFromLogger(l)
+	child.base = l.base.WithOptions(zap.AddCallerSkip(skip))
+	child.zaplog = l.zaplog.WithOptions(zap.AddCallerSkip(skip))
+	return child
+}
+
+// Name get logger name
+func (l *Logger) Name() string {
+	return l.name
+}
+
 // NewFromLogger new a logger from a logger
 func NewFromLogger(logger *Logger) *Logger {
 	return &Logger{
-		zaplog: logger.zaplog,
-		opt:    logger.opt,
+		name:      logger.name,
+		base:      logger.base,
+		zaplog:    logger.zaplog,
+		fields:    logger.fields,
+		opt:       logger.opt,
+		level:     logger.level,
+		file:      logger.file,
+		errorFile: logger.errorFile,
+		async:     logger.async,
+		drops:     logger.drops,
+		redact:    logger.redact,
+		counts:    logger.counts,
+		hooks:     logger.hooks,
+		closed:    logger.closed,
+		derived:   true,
 	}
 }
 
 // global default logger func
 
+// Sync flushes buffered entries of all registered loggers
+func Sync() error {
+	mu.Lock()
+	defer mu.Unlock()
+	var errs []error
+	for name, l := range loggers {
+		if err := l.Sync(); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes and closes all registered loggers and removes them from the registry,
+// otel logger providers are flushed but not shut down since they may be shared.
+// It returns ctx.Err() when ctx is done before all loggers are closed
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	closing := loggers
+	loggers = make(map[string]*Logger, 0)
+	defaultLogger = nil
+	mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		for name, l := range closing {
+			if l.opt.LoggerProvider != nil {
+				if err := l.opt.LoggerProvider.ForceFlush(ctx); err != nil {
+					errs = append(errs, fmt.Errorf("logger %s: %w", name, err))
+				}
+			}
+			if err := l.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("logger %s: %w", name, err))
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fatalHook flushes all registered loggers before exiting,
+// entries buffered by other loggers would be lost otherwise
+type fatalHook struct{}
+
+// OnWrite implements zapcore.CheckWriteHook
+func (fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	_ = Sync()
+	os.Exit(1)
+}
+
 // DebugContext default debug
 func DebugContext(ctx context.Context, msg string, fields ...zapcore.Field) {
 	GetDefaultLogger().Debug(ctx, msg, fields...)
@@ -1,10 +1,18 @@
 package log
 
 import (
+	"context"
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLoggerOptGetFilePath(t *testing.T) {
@@ -24,7 +32,153 @@ func TestLoggerOptGetFilePath(t *testing.T) {
 }
 
 func TestGetDefaultLogger(t *testing.T) {
+	t.Cleanup(func() { _ = Shutdown(context.Background()) })
 	assert.Equal(t, true, GetDefaultLogger() == nil)
-	logger := GetLogger("abc", &defaultLogOpt)
+	opt := defaultLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false)
+	logger := GetLogger("abc", &opt)
 	assert.Equal(t, logger, GetDefaultLogger())
 }
+
+func TestSetLevelConcurrently(t *testing.T) {
+	logger := newTestLogger(t, "concurrent", CommonLogOpt.WithLogLevel(zapcore.InfoLevel))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			logger.SetLevel(zapcore.DebugLevel)
+			logger.SetLevel(zapcore.WarnLevel)
+		}()
+		go func() {
+			defer wg.Done()
+			logger.Info(context.Background(), "concurrent log")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, zapcore.WarnLevel, logger.Level())
+	assert.False(t, logger.zaplog.Core().Enabled(zapcore.InfoLevel))
+	assert.True(t, logger.zaplog.Core().Enabled(zapcore.WarnLevel))
+}
+
+func TestChildLogger(t *testing.T) {
+	parent := newTestLogger(t, "parent", CommonLogOpt.WithTraceIDEnable(false))
+	child := parent.With(zap.String("request", "1")).Named("db")
+	grandchild := child.With(zap.String("table", "users"))
+
+	parent.Info(context.Background(), "parent log")
+	child.Info(context.Background(), "child log")
+	grandchild.Info(context.Background(), "grandchild log")
+
+	assert.Equal(t, "parent", parent.Name())
+	assert.Equal(t, "parent.db", grandchild.Name())
+	lines := readLogLines(t, parent.opt.GetLogFilePath())
+	assert.Equal(t, []map[string]any{
+		{"msg": "parent log", "name": "parent"},
+		{"msg": "child log", "name": "parent.db", "request": "1"},
+		{"msg": "grandchild log", "name": "parent.db", "request": "1", "table": "users"},
+	}, lines)
+
+	// children share level with parent
+	parent.SetLevel(zapcore.ErrorLevel)
+	assert.Equal(t, zapcore.ErrorLevel, grandchild.Level())
+}
+
+// newTestLogger new an unregistered logger with opt writing to a temp directory without console output,
+// the logger is closed when the test ends
+func newTestLogger(t *testing.T, name string, opt LoggerOpt) *Logger {
+	t.Helper()
+	opt = opt.WithDirectory(t.TempDir()).WithConsoleLog(false)
+	logger := NewLogger(name, &opt)
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger
+}
+
+// readLogLines reads json log file, dropping time, caller and level fields
+func readLogLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := make([]map[string]any, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := make(map[string]any)
+		assert.Nil(t, json.Unmarshal([]byte(line), &fields))
+		delete(fields, "time")
+		delete(fields, "caller")
+		delete(fields, "level")
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestShutdown(t *testing.T) {
+	opt := CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false).WithAsync(16, time.Hour, OverflowBlock)
+	logger := GetLogger("shutdown", &opt)
+	logger.Info(context.Background(), "before shutdown")
+	assert.Equal(t, logger, GetDefaultLogger())
+
+	assert.Nil(t, Shutdown(context.Background()))
+	assert.Nil(t, GetDefaultLogger())
+	assert.Empty(t, Loggers())
+	assert.Equal(t, 1, len(readLogLines(t, logger.opt.GetLogFilePath())))
+
+	// a logger with the same name can be created again
+	assert.NotEqual(t, logger, GetLogger("shutdown", &opt))
+	assert.Nil(t, Shutdown(context.Background()))
+}
+
+func TestClose(t *testing.T) {
+	testcases := []struct {
+		Name string
+		Opt  LoggerOpt
+	}{
+		{"closesync", CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false)},
+		{"closeasync", CommonLogOpt.WithDirectory(t.TempDir()).WithConsoleLog(false).WithAsync(16, time.Hour, OverflowBlock)},
+	}
+
+	for _, testcase := range testcases {
+		opt := testcase.Opt
+		opt.IsDefault = true
+		logger := GetLogger(testcase.Name, &opt)
+		child := logger.With(zap.String("request", "1"))
+		ctx := context.Background()
+
+		// closing a child leaves the outputs of its parent open
+		assert.Nil(t, child.Close(), testcase.Name)
+		logger.Info(ctx, "before close")
+		child.Info(ctx, "child before close")
+
+		assert.Nil(t, logger.Close(), testcase.Name)
+		assert.Nil(t, logger.Close(), testcase.Name)
+		assert.Nil(t, GetDefaultLogger(), testcase.Name)
+		assert.NotEqual(t, logger, GetLogger(testcase.Name, &opt), testcase.Name)
+		assert.Nil(t, Shutdown(ctx))
+
+		// entries after close are dropped instead of reopening the log file
+		logger.Info(ctx, "after close")
+		child.Info(ctx, "child after close")
+		assert.Equal(t, 2, len(readLogLines(t, logger.opt.GetLogFilePath())), testcase.Name)
+	}
+}
+
+func TestErrorLog(t *testing.T) {
+	logger := newTestLogger(t, "errorlog", CommonLogOpt.WithTraceIDEnable(false).WithErrorLog(5, 2, 7))
+	assert.Equal(t, filepath.Join(filepath.Dir(logger.opt.GetLogFilePath()), "errorlog.error.log"),
+		logger.opt.GetErrorLogFilePath())
+
+	ctx := context.Background()
+	logger.Info(ctx, "info")
+	logger.Warn(ctx, "warn")
+	logger.Error(ctx, "error")
+	assert.Equal(t, 3, len(readLogLines(t, logger.opt.GetLogFilePath())))
+
+	lines := readLogLines(t, logger.opt.GetErrorLogFilePath())
+	assert.Equal(t, 1, len(lines))
+	assert.Equal(t, "error", lines[0]["msg"])
+	assert.Contains(t, lines[0]["stack"], "TestErrorLog")
+
+	maxSize, maxBackups, maxAge := logger.errorFile.retention()
+	assert.Equal(t, []int{5, 2, 7}, []int{maxSize, maxBackups, maxAge})
+	assert.Nil(t, logger.Close())
+}
@@ -0,0 +1,393 @@
+package log
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder encodes entries as logfmt lines: key=value pairs separated by spaces,
+// nested objects and arrays are written as quoted json
+type logfmtEncoder struct {
+	cfg       *zapcore.EncoderConfig
+	buf       *buffer.Buffer
+	namespace string // prefix of keys added after OpenNamespace
+}
+
+// newLogfmtEncoder new a logfmt encoder
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{cfg: &cfg, buf: logfmtPool.Get()}
+}
+
+// Clone implements zapcore.Encoder
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{cfg: enc.cfg, buf: logfmtPool.Get(), namespace: enc.namespace}
+	_, _ = clone.buf.Write(enc.buf.Bytes())
+	return clone
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (enc *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := &logfmtEncoder{cfg: enc.cfg, buf: logfmtPool.Get()}
+	if enc.cfg.TimeKey != "" && enc.cfg.EncodeTime != nil {
+		enc.cfg.EncodeTime(ent.Time, line.valueEncoder(enc.cfg.TimeKey))
+	}
+	if enc.cfg.LevelKey != "" && enc.cfg.EncodeLevel != nil {
+		enc.cfg.EncodeLevel(ent.Level, line.valueEncoder(enc.cfg.LevelKey))
+	}
+	if enc.cfg.NameKey != "" && ent.LoggerName != "" {
+		line.AddString(enc.cfg.NameKey, ent.LoggerName)
+	}
+	if enc.cfg.CallerKey != "" && ent.Caller.Defined && enc.cfg.EncodeCaller != nil {
+		enc.cfg.EncodeCaller(ent.Caller, line.valueEncoder(enc.cfg.CallerKey))
+	}
+	if enc.cfg.MessageKey != "" {
+		line.AddString(enc.cfg.MessageKey, ent.Message)
+	}
+	// fields added by With
+	if enc.buf.Len() > 0 {
+		line.separate()
+		_, _ = line.buf.Write(enc.buf.Bytes())
+	}
+	line.namespace = enc.namespace
+	for _, field := range fields {
+		field.AddTo(line)
+	}
+	line.namespace = ""
+	if enc.cfg.StacktraceKey != "" && ent.Stack != "" {
+		line.AddString(enc.cfg.StacktraceKey, ent.Stack)
+	}
+	if enc.cfg.LineEnding != "" {
+		line.buf.AppendString(enc.cfg.LineEnding)
+	} else {
+		line.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+	return line.buf, nil
+}
+
+func (enc *logfmtEncoder) separate() {
+	if enc.buf.Len() > 0 {
+		enc.buf.AppendByte(' ')
+	}
+}
+
+func (enc *logfmtEncoder) addKey(key string) {
+	enc.separate()
+	if enc.namespace != "" {
+		key = enc.namespace + "." + key
+	}
+	enc.appendValue(key)
+	enc.buf.AppendByte('=')
+}
+
+// appendValue appends s, quoted when it is empty or contains spaces, quotes, '=' or control characters
+func (enc *logfmtEncoder) appendValue(s string) {
+	needQuote := s == ""
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			needQuote = true
+			break
+		}
+	}
+	if needQuote {
+		enc.buf.AppendString(strconv.Quote(s))
+		return
+	}
+	enc.buf.AppendString(s)
+}
+
+func (enc *logfmtEncoder) addJSON(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	enc.AddString(key, string(data))
+	return nil
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddArray(key, arr); err != nil {
+		return err
+	}
+	return enc.addJSON(key, m.Fields[key])
+}
+
+// AddObject implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := obj.MarshalLogObject(m); err != nil {
+		return err
+	}
+	return enc.addJSON(key, m.Fields)
+}
+
+// AddBinary implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddBinary(key string, value []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddByteString(key string, value []byte) {
+	enc.AddString(key, string(value))
+}
+
+// AddBool implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddBool(key string, value bool) {
+	enc.addKey(key)
+	enc.buf.AppendBool(value)
+}
+
+// AddComplex128 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddComplex128(key string, value complex128) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddComplex64(key string, value complex64) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+// AddDuration implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	if enc.cfg.EncodeDuration != nil {
+		enc.cfg.EncodeDuration(value, enc.valueEncoder(key))
+		return
+	}
+	enc.AddString(key, value.String())
+}
+
+// AddFloat64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddFloat64(key string, value float64) {
+	enc.addKey(key)
+	enc.appendFloat(value, 64)
+}
+
+// AddFloat32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddFloat32(key string, value float32) {
+	enc.addKey(key)
+	enc.appendFloat(float64(value), 32)
+}
+
+func (enc *logfmtEncoder) appendFloat(value float64, bitSize int) {
+	switch {
+	case math.IsNaN(value):
+		enc.buf.AppendString("NaN")
+	case math.IsInf(value, 1):
+		enc.buf.AppendString("+Inf")
+	case math.IsInf(value, -1):
+		enc.buf.AppendString("-Inf")
+	default:
+		enc.buf.AppendFloat(value, bitSize)
+	}
+}
+
+// AddInt implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt(key string, value int) {
+	enc.AddInt64(key, int64(value))
+}
+
+// AddInt64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt64(key string, value int64) {
+	enc.addKey(key)
+	enc.buf.AppendInt(value)
+}
+
+// AddInt32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt32(key string, value int32) {
+	enc.AddInt64(key, int64(value))
+}
+
+// AddInt16 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt16(key string, value int16) {
+	enc.AddInt64(key, int64(value))
+}
+
+// AddInt8 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt8(key string, value int8) {
+	enc.AddInt64(key, int64(value))
+}
+
+// AddString implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddString(key, value string) {
+	enc.addKey(key)
+	enc.appendValue(value)
+}
+
+// AddTime implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddTime(key string, value time.Time) {
+	if enc.cfg.EncodeTime != nil {
+		enc.cfg.EncodeTime(value, enc.valueEncoder(key))
+		return
+	}
+	enc.AddString(key, value.Format(time.RFC3339Nano))
+}
+
+// AddUint implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint(key string, value uint) {
+	enc.AddUint64(key, uint64(value))
+}
+
+// AddUint64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint64(key string, value uint64) {
+	enc.addKey(key)
+	enc.buf.AppendUint(value)
+}
+
+// AddUint32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint32(key string, value uint32) {
+	enc.AddUint64(key, uint64(value))
+}
+
+// AddUint16 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint16(key string, value uint16) {
+	enc.AddUint64(key, uint64(value))
+}
+
+// AddUint8 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint8(key string, value uint8) {
+	enc.AddUint64(key, uint64(value))
+}
+
+// AddUintptr implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUintptr(key string, value uintptr) {
+	enc.AddString(key, fmt.Sprintf("0x%x", value))
+}
+
+// AddReflected implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddReflected(key string, value interface{}) error {
+	return enc.addJSON(key, value)
+}
+
+// OpenNamespace implements zapcore.ObjectEncoder, keys added later are prefixed with "<key>."
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	if enc.namespace != "" {
+		key = enc.namespace + "." + key
+	}
+	enc.namespace = key
+}
+
+// valueEncoder returns an encoder writing the values appended by zap encoders
+// such as EncodeTime and EncodeLevel under key
+func (enc *logfmtEncoder) valueEncoder(key string) zapcore.PrimitiveArrayEncoder {
+	return &logfmtValueEncoder{enc: enc, key: key}
+}
+
+// logfmtValueEncoder writes every appended value under the same key,
+// zap encoders for time, level and caller append exactly one value
+type logfmtValueEncoder struct {
+	enc *logfmtEncoder
+	key string
+}
+
+// AppendBool implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendBool(value bool) {
+	v.enc.AddBool(v.key, value)
+}
+
+// AppendByteString implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendByteString(value []byte) {
+	v.enc.AddByteString(v.key, value)
+}
+
+// AppendComplex128 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendComplex128(value complex128) {
+	v.enc.AddComplex128(v.key, value)
+}
+
+// AppendComplex64 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendComplex64(value complex64) {
+	v.enc.AddComplex64(v.key, value)
+}
+
+// AppendFloat64 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendFloat64(value float64) {
+	v.enc.AddFloat64(v.key, value)
+}
+
+// AppendFloat32 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendFloat32(value float32) {
+	v.enc.AddFloat32(v.key, value)
+}
+
+// AppendInt implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendInt(value int) {
+	v.enc.AddInt(v.key, value)
+}
+
+// AppendInt64 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendInt64(value int64) {
+	v.enc.AddInt64(v.key, value)
+}
+
+// AppendInt32 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendInt32(value int32) {
+	v.enc.AddInt32(v.key, value)
+}
+
+// AppendInt16 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendInt16(value int16) {
+	v.enc.AddInt16(v.key, value)
+}
+
+// AppendInt8 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendInt8(value int8) {
+	v.enc.AddInt8(v.key, value)
+}
+
+// AppendString implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendString(value string) {
+	v.enc.AddString(v.key, value)
+}
+
+// AppendUint implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUint(value uint) {
+	v.enc.AddUint(v.key, value)
+}
+
+// AppendUint64 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUint64(value uint64) {
+	v.enc.AddUint64(v.key, value)
+}
+
+// AppendUint32 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUint32(value uint32) {
+	v.enc.AddUint32(v.key, value)
+}
+
+// AppendUint16 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUint16(value uint16) {
+	v.enc.AddUint16(v.key, value)
+}
+
+// AppendUint8 implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUint8(value uint8) {
+	v.enc.AddUint8(v.key, value)
+}
+
+// AppendUintptr implements zapcore.PrimitiveArrayEncoder
+func (v *logfmtValueEncoder) AppendUintptr(value uintptr) {
+	v.enc.AddUintptr(v.key, value)
+}
+
+// AppendDuration implements zapcore.ArrayEncoder for duration encoders
+func (v *logfmtValueEncoder) AppendDuration(value time.Duration) {
+	v.enc.AddString(v.key, value.String())
+}
+
+// AppendTime implements zapcore.ArrayEncoder for time encoders
+func (v *logfmtValueEncoder) AppendTime(value time.Time) {
+	v.enc.AddString(v.key, value.Format(time.RFC3339Nano))
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	cfg := logLogfmtEncodeCfg
+	cfg.TimeKey = ""
+	enc := newLogfmtEncoder(cfg)
+	zap.String("name", "app").AddTo(enc)
+	child := enc.Clone()
+	zap.Namespace("req").AddTo(child)
+
+	testcases := []struct {
+		Enc    zapcore.Encoder
+		Msg    string
+		Fields []zap.Field
+		Want   string
+	}{
+		{enc, "hello", []zap.Field{zap.Int("n", 1), zap.Bool("ok", true)}, "level=info msg=hello name=app n=1 ok=true\n"},
+		{enc, "with space", []zap.Field{zap.String("empty", ""), zap.String("q", `a"b`), zap.String("eq", "a=b")},
+			`level=info msg="with space" name=app empty="" q="a\"b" eq="a=b"` + "\n"},
+		{enc, "types", []zap.Field{
+			zap.Duration("d", time.Second),
+			zap.Error(errors.New("boom")),
+			zap.Strings("arr", []string{"a", "b"}),
+			zap.Any("obj", map[string]int{"a": 1}),
+		}, `level=info msg=types name=app d=1s error=boom arr="[\"a\",\"b\"]" obj="{\"a\":1}"` + "\n"},
+		{child, "namespace", []zap.Field{zap.String("path", "/")}, "level=info msg=namespace name=app req.path=/\n"},
+	}
+
+	for _, testcase := range testcases {
+		buf, err := testcase.Enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Message: testcase.Msg}, testcase.Fields)
+		assert.Nil(t, err)
+		assert.Equal(t, testcase.Want, buf.String())
+		buf.Free()
+	}
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogrSink is a logr.LogSink writing to a Logger,
+// V(0) logs at info and greater verbosity logs at debug
+type LogrSink struct {
+	logger *Logger
+}
+
+// NewLogr new a logr logger writing to logger
+func NewLogr(logger *Logger) logr.Logger {
+	return logr.New(&LogrSink{logger: logger})
+}
+
+// Init implements logr.LogSink,
+// the caller is info.CallDepth frames above the caller of the sink
+func (s *LogrSink) Init(info logr.RuntimeInfo) {
+	s.logger = s.logger.WithCallerSkip(info.CallDepth + 1)
+}
+
+// Enabled implements logr.LogSink
+func (s *LogrSink) Enabled(level int) bool {
+	return s.logger.level.Enabled(logrLevel(level))
+}
+
+// Info implements logr.LogSink
+func (s *LogrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.logger.logAt(context.Background(), logrLevel(level), msg, logrFields(keysAndValues)...)
+}
+
+// Error implements logr.LogSink
+func (s *LogrSink) Error(err error, msg string, keysAndValues ...any) {
+	fields := append(logrFields(keysAndValues), zap.Error(err))
+	s.logger.logAt(context.Background(), zapcore.ErrorLevel, msg, fields...)
+}
+
+// WithValues implements logr.LogSink
+func (s *LogrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &LogrSink{logger: s.logger.With(logrFields(keysAndValues)...)}
+}
+
+// WithName implements logr.LogSink
+func (s *LogrSink) WithName(name string) logr.LogSink {
+	return &LogrSink{logger: s.logger.Named(name)}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink
+func (s *LogrSink) WithCallDepth(depth int) logr.LogSink {
+	return &LogrSink{logger: s.logger.WithCallerSkip(depth)}
+}
+
+// logrLevel maps a logr verbosity to a zap level
+func logrLevel(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// logrFields converts logr key value pairs to zap fields,
+// a value without key is logged with key "!BADKEY" as slog does
+func logrFields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, zap.Any("!BADKEY", keysAndValues[i]))
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		value := keysAndValues[i+1]
+		if marshaler, ok := value.(logr.Marshaler); ok {
+			value = marshaler.MarshalLog()
+		}
+		fields = append(fields, zap.Any(key, value))
+	}
+	return fields
+}
@@ -0,0 +1,31 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogr(t *testing.T) {
+	logger := newTestLogger(t, "logr", CommonLogOpt.WithTraceIDEnable(false).
+		WithLogLevel(zapcore.InfoLevel))
+	logr := NewLogr(logger)
+
+	logr.V(1).Info("debug is disabled")
+	logr.WithName("sub").WithValues("a", 1).Info("info", "b", "c", "odd")
+	logr.Error(errors.New("boom"), "error")
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 2, len(lines))
+	delete(lines[1], "stack")
+	assert.Equal(t, []map[string]any{
+		{"msg": "info", "name": "logr.sub", "a": float64(1), "b": "c", "!BADKEY": "odd"},
+		{"msg": "error", "name": "logr", "error": "boom"},
+	}, lines)
+	for _, caller := range readLogCallers(t, logger.opt.GetLogFilePath()) {
+		assert.True(t, strings.HasPrefix(caller, "logr_test.go:"), caller)
+	}
+}
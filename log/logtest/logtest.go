@@ -0,0 +1,231 @@
+// Package logtest captures entries of a log.Logger in memory for tests
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// traceIDKey field key of trace ids written by log.Logger
+const traceIDKey = "trace_id"
+
+// Entry is a captured log entry
+type Entry struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Message string
+	Fields  map[string]any // logger name, trace id, ctx and call fields, as encoded by zap
+}
+
+// String formats the entry on one line for failure messages
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %q %v", e.Level, e.Message, e.Fields)
+}
+
+// Entries is a list of captured entries, its methods filter the list
+type Entries []Entry
+
+// Logs holds the entries captured by a test logger
+type Logs struct {
+	observed *observer.ObservedLogs
+}
+
+// New new a logger capturing entries at every level in memory,
+// the logger is named after the test, has trace ids enabled and is closed when the test ends
+func New(tb testing.TB) (*log.Logger, *Logs) {
+	opt := log.CommonLogOpt.WithLogLevel(zapcore.DebugLevel).WithTraceIDEnable(true)
+	return NewWithOpt(tb, opt)
+}
+
+// NewWithOpt new a logger capturing entries in memory with opt,
+// the log file, error log file and console log of opt are disabled
+// and the logger is not registered, so GetLogger never returns it.
+// Fatal panics with its message after the entry is captured instead of exiting, see AssertFatal
+func NewWithOpt(tb testing.TB, opt log.LoggerOpt) (*log.Logger, *Logs) {
+	tb.Helper()
+	core, observed := observer.New(zapcore.DebugLevel)
+	opt = opt.WithFileLog(false).WithConsoleLog(false).WithSinks(log.NewCoreSink(core)).
+		WithFatalHook(zapcore.WriteThenPanic)
+	opt.ErrorLog = nil
+	opt.IsDefault = false
+	logger := log.NewLogger(tb.Name(), &opt)
+	tb.Cleanup(func() { _ = logger.Close() })
+	return logger, &Logs{observed: observed}
+}
+
+// All returns every captured entry in order
+func (l *Logs) All() Entries {
+	observed := l.observed.All()
+	entries := make(Entries, 0, len(observed))
+	for _, e := range observed {
+		entries = append(entries, Entry{
+			Level:   e.Level,
+			Time:    e.Time,
+			Message: e.Message,
+			Fields:  e.ContextMap(),
+		})
+	}
+	return entries
+}
+
+// Len returns the number of captured entries
+func (l *Logs) Len() int {
+	return l.observed.Len()
+}
+
+// Reset drops the captured entries
+func (l *Logs) Reset() {
+	l.observed.TakeAll()
+}
+
+// Level returns captured entries at level
+func (l *Logs) Level(level zapcore.Level) Entries {
+	return l.All().Level(level)
+}
+
+// Message returns captured entries with message msg
+func (l *Logs) Message(msg string) Entries {
+	return l.All().Message(msg)
+}
+
+// Field returns captured entries with field key equal to value
+func (l *Logs) Field(key string, value any) Entries {
+	return l.All().Field(key, value)
+}
+
+// TraceID returns captured entries logged with trace id
+func (l *Logs) TraceID(traceID string) Entries {
+	return l.All().TraceID(traceID)
+}
+
+// AssertLogged fails tb unless an entry at level with message msg and fields was captured,
+// fields of the entry not in fields are ignored
+func (l *Logs) AssertLogged(tb testing.TB, level zapcore.Level, msg string, fields ...zap.Field) bool {
+	tb.Helper()
+	entries := l.All()
+	candidates := entries.Level(level).Message(msg)
+	if len(candidates) == 0 {
+		return assert.Fail(tb, fmt.Sprintf("no %s entry %q was logged", level, msg), "captured:\n%s", entries)
+	}
+	want := fieldMap(fields)
+	for _, entry := range candidates {
+		if assert.ObjectsAreEqual(want, subset(entry.Fields, want)) {
+			return true
+		}
+	}
+	// show the diff against the first entry with the same level and message
+	return assert.Equal(tb, want, subset(candidates[0].Fields, want), "fields of %s entry %q", level, msg)
+}
+
+// AssertNotLogged fails tb when an entry at level with message msg was captured
+func (l *Logs) AssertNotLogged(tb testing.TB, level zapcore.Level, msg string) bool {
+	tb.Helper()
+	if entries := l.All().Level(level).Message(msg); len(entries) > 0 {
+		return assert.Fail(tb, fmt.Sprintf("unexpected %s entry %q was logged", level, msg), "captured:\n%s", entries)
+	}
+	return true
+}
+
+// AssertFatal fails tb unless fn logs a fatal entry with message msg,
+// the panic of the fatal entry is recovered
+func (l *Logs) AssertFatal(tb testing.TB, msg string, fn func()) bool {
+	tb.Helper()
+	recovered := func() (recovered any) {
+		defer func() { recovered = recover() }()
+		fn()
+		return nil
+	}()
+	if recovered != msg {
+		return assert.Fail(tb, fmt.Sprintf("no fatal entry %q was logged", msg), "recovered: %v", recovered)
+	}
+	return l.AssertLogged(tb, zapcore.FatalLevel, msg)
+}
+
+// AssertMessages fails tb unless the captured messages are msgs in order
+func (l *Logs) AssertMessages(tb testing.TB, msgs ...string) bool {
+	tb.Helper()
+	return assert.Equal(tb, msgs, l.All().Messages())
+}
+
+// Level returns entries at level
+func (e Entries) Level(level zapcore.Level) Entries {
+	return e.filter(func(entry Entry) bool { return entry.Level == level })
+}
+
+// Message returns entries with message msg
+func (e Entries) Message(msg string) Entries {
+	return e.filter(func(entry Entry) bool { return entry.Message == msg })
+}
+
+// Field returns entries with field key equal to value,
+// numbers are compared by value, so Field("n", 1) matches zap.Int64("n", 1)
+func (e Entries) Field(key string, value any) Entries {
+	return e.filter(func(entry Entry) bool {
+		got, ok := entry.Fields[key]
+		return ok && assert.ObjectsAreEqualValues(value, got)
+	})
+}
+
+// TraceID returns entries logged with trace id
+func (e Entries) TraceID(traceID string) Entries {
+	return e.Field(traceIDKey, traceID)
+}
+
+// Messages returns messages of the entries
+func (e Entries) Messages() []string {
+	msgs := make([]string, 0, len(e))
+	for _, entry := range e {
+		msgs = append(msgs, entry.Message)
+	}
+	return msgs
+}
+
+// String formats one entry per line
+func (e Entries) String() string {
+	if len(e) == 0 {
+		return "  (none)"
+	}
+	lines := make([]string, 0, len(e))
+	for _, entry := range e {
+		lines = append(lines, "  "+entry.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e Entries) filter(keep func(Entry) bool) Entries {
+	var entries Entries
+	for _, entry := range e {
+		if keep(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// fieldMap encodes fields the way captured entry fields are encoded
+func fieldMap(fields []zap.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+// subset returns the fields whose keys are in want
+func subset(fields map[string]any, want map[string]any) map[string]any {
+	got := make(map[string]any, len(want))
+	for key := range want {
+		if value, ok := fields[key]; ok {
+			got[key] = value
+		}
+	}
+	return got
+}
@@ -0,0 +1,90 @@
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onesaltedseafish/go-utils/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestLogs(t *testing.T) {
+	logger, logs := New(t)
+	ctx := log.WithTraceID(context.Background(), "trace")
+
+	logger.Debug(ctx, "debug", zap.Int("n", 1))
+	logger.With(zap.String("user", "alice")).Info(context.Background(), "info")
+	logger.Error(ctx, "error", zap.Error(fmt.Errorf("boom")))
+
+	assert.Equal(t, 3, logs.Len())
+	assert.Equal(t, []string{"debug", "error"}, logs.TraceID("trace").Messages())
+	assert.Equal(t, []string{"info"}, logs.Field("user", "alice").Messages())
+	assert.Equal(t, []string{"debug"}, logs.Field("n", 1).Messages())
+	assert.Equal(t, []string{"error"}, logs.Level(zapcore.ErrorLevel).Messages())
+	assert.Equal(t, t.Name(), logs.Message("info")[0].Fields["name"])
+
+	logs.AssertMessages(t, "debug", "info", "error")
+	logs.AssertLogged(t, zapcore.DebugLevel, "debug", zap.Int("n", 1), zap.String("trace_id", "trace"))
+	logs.AssertNotLogged(t, zapcore.InfoLevel, "debug")
+
+	// fatal entries panic instead of exiting the test binary
+	logs.AssertFatal(t, "fatal", func() { logger.Fatal(ctx, "fatal") })
+
+	logs.Reset()
+	assert.Equal(t, 0, logs.Len())
+	// test loggers are not registered
+	assert.Empty(t, log.Loggers())
+	assert.Nil(t, log.GetDefaultLogger())
+}
+
+func TestAssertFailures(t *testing.T) {
+	logger, logs := NewWithOpt(t, log.CommonLogOpt.WithLogLevel(zapcore.InfoLevel))
+	logger.Debug(context.Background(), "debug is disabled")
+	logger.Info(context.Background(), "info", zap.Int("n", 1))
+
+	testcases := []struct {
+		Assert func(tb testing.TB) bool
+		Want   string
+	}{
+		{
+			Assert: func(tb testing.TB) bool { return logs.AssertLogged(tb, zapcore.InfoLevel, "missing") },
+			Want:   `no info entry "missing" was logged`,
+		},
+		{
+			Assert: func(tb testing.TB) bool { return logs.AssertLogged(tb, zapcore.InfoLevel, "info", zap.Int("n", 2)) },
+			Want:   `- (string) (len=1) "n": (int64) 2`,
+		},
+		{
+			Assert: func(tb testing.TB) bool { return logs.AssertNotLogged(tb, zapcore.InfoLevel, "info") },
+			Want:   `unexpected info entry "info" was logged`,
+		},
+		{
+			Assert: func(tb testing.TB) bool { return logs.AssertMessages(tb, "debug is disabled", "info") },
+			Want:   `- (string) (len=17) "debug is disabled",`,
+		},
+		{
+			Assert: func(tb testing.TB) bool { return logs.AssertFatal(tb, "fatal", func() {}) },
+			Want:   `no fatal entry "fatal" was logged`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		ft := &fakeT{TB: t}
+		assert.False(t, testcase.Assert(ft))
+		assert.Equal(t, 1, len(ft.errors))
+		assert.Contains(t, ft.errors[0], testcase.Want)
+	}
+}
@@ -0,0 +1,144 @@
+package log
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// metricsLevels levels counted by Metrics, the levels Logger logs at
+var metricsLevels = []zapcore.Level{
+	zapcore.DebugLevel,
+	zapcore.InfoLevel,
+	zapcore.WarnLevel,
+	zapcore.ErrorLevel,
+	zapcore.FatalLevel,
+}
+
+// Metrics counts log entries by logger name and level and entries dropped by each logger,
+// it is shared by the loggers created with it in LoggerOpt.Metrics
+// and serves the counts in prometheus text format
+type Metrics struct {
+	mu      sync.Mutex
+	entries map[string]*levelCounts
+	drops   map[string][]*dropCounter
+}
+
+// LoggerMetrics counts of a logger,
+// children of a logger share its drops, so they are only counted under the logger name
+type LoggerMetrics struct {
+	Name string
+	// entries written or dropped by sampling, rate limiting or buffers, not those dropped by hooks
+	Entries     map[zapcore.Level]uint64
+	Sampled     uint64 // entries dropped by sampling
+	RateLimited uint64 // entries dropped by rate limiting
+	BufferFull  uint64 // entries dropped by a full async buffer
+}
+
+// levelCounts counts entries of a logger name by level
+type levelCounts [zapcore.FatalLevel - zapcore.DebugLevel + 1]atomic.Uint64
+
+// add counts an entry at level, a nil levelCounts counts nothing
+func (c *levelCounts) add(level zapcore.Level) {
+	if c == nil || level < zapcore.DebugLevel || level > zapcore.FatalLevel {
+		return
+	}
+	c[level-zapcore.DebugLevel].Add(1)
+}
+
+// NewMetrics new a metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		entries: make(map[string]*levelCounts),
+		drops:   make(map[string][]*dropCounter),
+	}
+}
+
+// counts returns the counts of logger name, nil when m is nil
+func (m *Metrics) counts(name string) *levelCounts {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.entries[name]
+	if !ok {
+		c = new(levelCounts)
+		m.entries[name] = c
+	}
+	return c
+}
+
+// addDrops reports dropped counts of drops under logger name
+func (m *Metrics) addDrops(name string, drops *dropCounter) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drops[name] = append(m.drops[name], drops)
+	if _, ok := m.entries[name]; !ok {
+		m.entries[name] = new(levelCounts)
+	}
+}
+
+// Snapshot returns current counts of every logger sorted by name
+func (m *Metrics) Snapshot() []LoggerMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make([]LoggerMetrics, 0, len(m.entries))
+	for name, counts := range m.entries {
+		lm := LoggerMetrics{Name: name, Entries: make(map[zapcore.Level]uint64, len(metricsLevels))}
+		for _, level := range metricsLevels {
+			lm.Entries[level] = counts[level-zapcore.DebugLevel].Load()
+		}
+		for _, drops := range m.drops[name] {
+			lm.Sampled += drops.sampled.Load()
+			lm.RateLimited += drops.limited.Load()
+			lm.BufferFull += drops.bufferFull.Load()
+		}
+		snapshot = append(snapshot, lm)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Name < snapshot[j].Name })
+	return snapshot
+}
+
+// ServeHTTP implements http.Handler, writing the counts in prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var b strings.Builder
+	snapshot := m.Snapshot()
+	b.WriteString("# HELP log_entries_total Log entries by logger and level.\n")
+	b.WriteString("# TYPE log_entries_total counter\n")
+	for _, lm := range snapshot {
+		for _, level := range metricsLevels {
+			fmt.Fprintf(&b, "log_entries_total{logger=%s,level=%s} %d\n",
+				quoteLabel(lm.Name), quoteLabel(level.String()), lm.Entries[level])
+		}
+	}
+	b.WriteString("# HELP log_dropped_total Log entries dropped by logger and reason.\n")
+	b.WriteString("# TYPE log_dropped_total counter\n")
+	for _, lm := range snapshot {
+		for _, dropped := range []struct {
+			reason string
+			count  uint64
+		}{{"sampled", lm.Sampled}, {"rate_limited", lm.RateLimited}, {"buffer_full", lm.BufferFull}} {
+			fmt.Fprintf(&b, "log_dropped_total{logger=%s,reason=%s} %d\n",
+				quoteLabel(lm.Name), quoteLabel(dropped.reason), dropped.count)
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// labelEscaper escapes label values as prometheus text format expects
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel quotes a label value
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	logger := newTestLogger(t, "metrics", CommonLogOpt.WithLogLevel(zapcore.InfoLevel).
+		WithSampling(time.Minute, 1, 0).WithMetrics(metrics))
+	ctx := context.Background()
+
+	logger.Debug(ctx, "debug is disabled")
+	for i := 0; i < 3; i++ {
+		logger.Info(ctx, "hot path")
+	}
+	logger.With().Warn(ctx, "warn")
+	logger.Named("db").Error(ctx, "db error")
+
+	counts := func(info, warn, err uint64) map[zapcore.Level]uint64 {
+		return map[zapcore.Level]uint64{
+			zapcore.DebugLevel: 0,
+			zapcore.InfoLevel:  info,
+			zapcore.WarnLevel:  warn,
+			zapcore.ErrorLevel: err,
+			zapcore.FatalLevel: 0,
+		}
+	}
+	assert.Equal(t, []LoggerMetrics{
+		{Name: "metrics", Entries: counts(3, 1, 0), Sampled: 2},
+		{Name: "metrics.db", Entries: counts(0, 0, 1)},
+	}, metrics.Snapshot())
+
+	resp := httptest.NewRecorder()
+	metrics.ServeHTTP(resp, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header().Get("Content-Type"))
+	for _, line := range []string{
+		"# TYPE log_entries_total counter",
+		`log_entries_total{logger="metrics",level="info"} 3`,
+		`log_entries_total{logger="metrics.db",level="error"} 1`,
+		`log_entries_total{logger="metrics",level="debug"} 0`,
+		`log_dropped_total{logger="metrics",reason="sampled"} 2`,
+		`log_dropped_total{logger="metrics.db",reason="rate_limited"} 0`,
+	} {
+		assert.True(t, strings.Contains(string(body), line+"\n"), line)
+	}
+}
+
+func TestQuoteLabel(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, quoteLabel("a\\b\"c\nd"))
+}
@@ -0,0 +1,125 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RecoverOption configures Recover and Go
+type RecoverOption func(*recoverOpt)
+
+// recoverOpt options of Recover and Go
+type recoverOpt struct {
+	repanic bool
+	onPanic func(ctx context.Context, recovered any, stack []byte)
+	label   string
+}
+
+// Repanic panics again with the recovered value after it is logged and reported,
+// the logger is synced first so the entry survives the crash
+func Repanic() RecoverOption {
+	return func(o *recoverOpt) {
+		o.repanic = true
+	}
+}
+
+// OnPanic calls fn with the recovered value and the stack of the panic after it is logged,
+// e.g. to send it to an error tracker
+func OnPanic(fn func(ctx context.Context, recovered any, stack []byte)) RecoverOption {
+	return func(o *recoverOpt) {
+		o.onPanic = fn
+	}
+}
+
+// GoroutineLabel logs label in the "goroutine" field,
+// goroutines started by Go also carry it as a pprof label
+func GoroutineLabel(label string) RecoverOption {
+	return func(o *recoverOpt) {
+		o.label = label
+	}
+}
+
+// Recover recovers a panic and logs it at error level with its stack trace via logger,
+// or the default logger when logger is nil. It must be deferred directly:
+//
+//	defer log.Recover(ctx, logger)
+func Recover(ctx context.Context, logger *Logger, opts ...RecoverOption) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	handlePanic(ctx, logger, recovered, opts)
+}
+
+// Go runs fn in a new goroutine that logs a panic of fn instead of crashing the process,
+// unless Repanic is given
+func Go(ctx context.Context, logger *Logger, fn func(ctx context.Context), opts ...RecoverOption) {
+	var o recoverOpt
+	for _, opt := range opts {
+		opt(&o)
+	}
+	go func() {
+		defer Recover(ctx, logger, opts...)
+		if o.label == "" {
+			fn(ctx)
+			return
+		}
+		pprof.Do(ctx, pprof.Labels("goroutine", o.label), fn)
+	}()
+}
+
+// handlePanic logs, reports and optionally re-panics a recovered value
+func handlePanic(ctx context.Context, logger *Logger, recovered any, opts []RecoverOption) {
+	var o recoverOpt
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if logger == nil {
+		logger = GetDefaultLogger()
+	}
+	if logger != nil {
+		fields := []zap.Field{zap.String("panic", fmt.Sprint(recovered))}
+		if err, ok := recovered.(error); ok {
+			fields = append(fields, zap.Error(err))
+		}
+		if o.label != "" {
+			fields = append(fields, zap.String("goroutine", o.label))
+		}
+		logger.log(ctx, zapcore.ErrorLevel, logger.callerFunc(panicPC(), time.Time{}), "panic recovered", fields...)
+	}
+	if o.onPanic != nil {
+		o.onPanic(ctx, recovered, debug.Stack())
+	}
+	if o.repanic {
+		if logger != nil {
+			_ = logger.Sync()
+		}
+		panic(recovered)
+	}
+}
+
+// panicPC returns the pc of the function that panicked, 0 when it is not found
+func panicPC() uintptr {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		} else if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return frame.PC
+		}
+		if !more {
+			return 0
+		}
+	}
+}
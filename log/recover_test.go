@@ -0,0 +1,66 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	logger := newTestLogger(t, "recover", CommonLogOpt)
+	ctx := WithTraceID(context.Background(), "trace")
+
+	var reported any
+	func() {
+		defer Recover(ctx, logger, GoroutineLabel("worker"), OnPanic(func(_ context.Context, recovered any, stack []byte) {
+			reported = recovered
+			assert.Contains(t, string(stack), "TestRecover")
+		}))
+		panic("boom")
+	}()
+	assert.Equal(t, "boom", reported)
+
+	assert.Panics(t, func() {
+		defer Recover(ctx, logger, Repanic())
+		panic(errors.New("again"))
+	})
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0]["stack"], "TestRecover")
+	delete(lines[0], "stack")
+	delete(lines[1], "stack")
+	assert.Equal(t, []map[string]any{
+		{"msg": "panic recovered", "name": "recover", "trace_id": "trace", "panic": "boom", "goroutine": "worker"},
+		{"msg": "panic recovered", "name": "recover", "trace_id": "trace", "panic": "again", "error": "again"},
+	}, lines)
+
+	// the caller is the line that panicked
+	assert.Equal(t, []string{"recover_test.go:22", "recover_test.go:28"}, readLogCallers(t, logger.opt.GetLogFilePath()))
+}
+
+func TestGo(t *testing.T) {
+	logger := newTestLogger(t, "go", CommonLogOpt.WithTraceIDEnable(false))
+
+	done := make(chan any)
+	Go(context.Background(), logger, func(ctx context.Context) {
+		var m map[string]int
+		m["nil map"]++
+	}, GoroutineLabel("writer"), OnPanic(func(_ context.Context, recovered any, _ []byte) {
+		done <- recovered
+	}))
+
+	select {
+	case recovered := <-done:
+		assert.Contains(t, recovered.(error).Error(), "nil map")
+	case <-time.After(5 * time.Second):
+		t.Fatal("panic not recovered")
+	}
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 1, len(lines))
+	assert.Equal(t, "writer", lines[0]["goroutine"])
+	assert.Equal(t, "assignment to entry in nil map", lines[0]["panic"])
+}
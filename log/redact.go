@@ -0,0 +1,231 @@
+package log
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaskStrategy decides how sensitive values are masked
+type MaskStrategy int
+
+const (
+	// MaskFull replaces the value with "******"
+	MaskFull MaskStrategy = iota
+	// MaskPartial keeps the last 4 characters of values longer than 8 characters
+	MaskPartial
+	// MaskHash replaces the value with a sha256 prefix, so equal values can still be correlated
+	MaskHash
+)
+
+const fullMask = "******"
+
+var (
+	// CreditCardPattern matches credit card numbers
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// BearerTokenPattern matches bearer tokens of authorization headers
+	BearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+)
+
+// RedactOpt configures redaction of sensitive data in messages and fields
+type RedactOpt struct {
+	Keys     []string         // fields whose key contains one of Keys, case insensitive, are masked entirely
+	Patterns []*regexp.Regexp // matches in messages and string values are masked
+	Strategy MaskStrategy
+}
+
+// redactor masks sensitive data as configured by RedactOpt
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	strategy MaskStrategy
+}
+
+// newRedactor new a redactor, nil when opt is nil
+func newRedactor(opt *RedactOpt) *redactor {
+	if opt == nil {
+		return nil
+	}
+	r := &redactor{patterns: opt.Patterns, strategy: opt.Strategy}
+	for _, key := range opt.Keys {
+		r.keys = append(r.keys, strings.ToLower(key))
+	}
+	return r
+}
+
+// message masks pattern matches in msg
+func (r *redactor) message(msg string) string {
+	if r == nil {
+		return msg
+	}
+	return r.value(msg)
+}
+
+// fields masks sensitive fields, fields is copied before being changed
+func (r *redactor) fields(fields []zap.Field) []zap.Field {
+	if r == nil {
+		return fields
+	}
+	var redacted []zap.Field
+	for i, field := range fields {
+		masked, ok := r.field(field)
+		if !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]zap.Field(nil), fields...)
+		}
+		redacted[i] = masked
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// field masks field, the second value reports whether field was changed
+func (r *redactor) field(field zap.Field) (zap.Field, bool) {
+	if field.Type == zapcore.NamespaceType {
+		return field, false
+	}
+	if r.sensitiveKey(field.Key) {
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		return zap.String(field.Key, r.mask(fmt.Sprint(enc.Fields[field.Key]))), true
+	}
+	var value string
+	switch field.Type {
+	case zapcore.StringType:
+		value = field.String
+	case zapcore.ByteStringType:
+		value = string(field.Interface.([]byte))
+	case zapcore.ErrorType:
+		value = field.Interface.(error).Error()
+	case zapcore.StringerType:
+		value = field.Interface.(fmt.Stringer).String()
+	case zapcore.ReflectType, zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.InlineMarshalerType:
+		return r.nested(field)
+	default:
+		return field, false
+	}
+	if redacted := r.value(value); redacted != value {
+		return zap.String(field.Key, redacted), true
+	}
+	return field, false
+}
+
+// nested masks maps, slices, objects and arrays at every depth,
+// the value is encoded as zap does and decoded from json so nested reflected values are walked too
+func (r *redactor) nested(field zap.Field) (zap.Field, bool) {
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	var encoded any = enc.Fields
+	if field.Type != zapcore.InlineMarshalerType {
+		encoded = enc.Fields[field.Key]
+	}
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return field, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return field, false
+	}
+	value, changed := r.walk(value)
+	if !changed {
+		return field, false
+	}
+	if field.Type == zapcore.InlineMarshalerType {
+		return zap.Inline(inlineMap(value.(map[string]any))), true
+	}
+	return zap.Any(field.Key, value), true
+}
+
+// walk masks sensitive keys and pattern matches in a value decoded from json,
+// the second value reports whether value was changed
+func (r *redactor) walk(value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		redacted := r.value(v)
+		return redacted, redacted != v
+	case map[string]any:
+		changed := false
+		for key, inner := range v {
+			if r.sensitiveKey(key) {
+				v[key], changed = r.mask(fmt.Sprint(inner)), true
+				continue
+			}
+			if redacted, ok := r.walk(inner); ok {
+				v[key], changed = redacted, true
+			}
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, inner := range v {
+			if redacted, ok := r.walk(inner); ok {
+				v[i], changed = redacted, true
+			}
+		}
+		return v, changed
+	default:
+		return value, false
+	}
+}
+
+// inlineMap adds its keys to the enclosing object
+type inlineMap map[string]any
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (m inlineMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for key, value := range m {
+		if err := enc.AddReflected(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// value masks pattern matches in s
+func (r *redactor) value(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, r.mask)
+	}
+	return s
+}
+
+func (r *redactor) mask(s string) string {
+	switch r.strategy {
+	case MaskPartial:
+		if len(s) <= 8 {
+			return fullMask
+		}
+		return fullMask + s[len(s)-4:]
+	case MaskHash:
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:])[:16]
+	default:
+		return fullMask
+	}
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor(&RedactOpt{
+		Keys:     []string{"password", "Authorization"},
+		Patterns: []*regexp.Regexp{CreditCardPattern, BearerTokenPattern, EmailPattern},
+	})
+	fields := []zap.Field{
+		zap.String("db_password", "secret"),
+		zap.Int("password_len", 6),
+		zap.String("header", "Authorization: Bearer abc.def-123"),
+		zap.String("card", "4111 1111 1111 1111"),
+		zap.Error(errors.New("user a@b.com not found")),
+		zap.Int("n", 1),
+	}
+	redacted := r.fields(fields)
+
+	assert.Equal(t, []zap.Field{
+		zap.String("db_password", "******"),
+		zap.String("password_len", "******"),
+		zap.String("header", "Authorization: ******"),
+		zap.String("card", "******"),
+		zap.String("error", "user ****** not found"),
+		zap.Int("n", 1),
+	}, redacted)
+	assert.Equal(t, zap.String("db_password", "secret"), fields[0], "fields of caller must not be changed")
+	assert.Equal(t, "pay with ******", r.message("pay with 4111-1111-1111-1111"))
+
+	var nilRedactor *redactor
+	assert.Equal(t, fields, nilRedactor.fields(fields))
+	assert.Nil(t, newRedactor(nil))
+}
+
+func TestMaskStrategy(t *testing.T) {
+	testcases := []struct {
+		Strategy MaskStrategy
+		Value    string
+		Want     string
+	}{
+		{MaskFull, "4111111111111111", "******"},
+		{MaskPartial, "4111111111111111", "******1111"},
+		{MaskPartial, "short", "******"},
+		{MaskHash, "secret", "sha256:2bb80d537b1da3e3"},
+	}
+
+	for _, testcase := range testcases {
+		r := newRedactor(&RedactOpt{Strategy: testcase.Strategy})
+		assert.Equal(t, testcase.Want, r.mask(testcase.Value))
+	}
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	logger := newTestLogger(t, "redact", CommonLogOpt.WithTraceIDEnable(false).
+		WithRedaction(RedactOpt{Keys: []string{"token"}, Patterns: []*regexp.Regexp{EmailPattern}}))
+
+	logger.With(zap.String("token", "abc")).Info(
+		WithFields(context.Background(), zap.String("user", "a@b.com")),
+		"login a@b.com",
+	)
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "login ******", "name": "redact", "token": "******", "user": "******"},
+	}, readLogLines(t, logger.opt.GetLogFilePath()))
+}
+
+func TestNestedRedaction(t *testing.T) {
+	logger := newTestLogger(t, "nestedredact", CommonLogOpt.WithTraceIDEnable(false).
+		WithRedaction(RedactOpt{Keys: []string{"password"}, Patterns: []*regexp.Regexp{EmailPattern}}))
+	ctx := context.Background()
+
+	logger.Info(ctx, "map", zap.Any("req", map[string]any{
+		"password": "hunter2",
+		"email":    "a@b.com",
+		"n":        1,
+		"inner":    map[string]any{"emails": []string{"x@y.com", "plain"}},
+	}))
+	logger.Info(ctx, "array", zap.Strings("emails", []string{"c@d.com"}))
+	logger.Info(ctx, "dict", zap.Dict("user", zap.String("db_password", "secret"), zap.Int("id", 7)))
+	slog.New(NewSlogHandler(logger)).Info("slog", slog.Group("user", "email", "e@f.com", "id", 7))
+	logger.Info(ctx, "unchanged", zap.Any("ids", []int{1, 2}))
+
+	assert.Equal(t, []map[string]any{
+		{"msg": "map", "name": "nestedredact", "req": map[string]any{
+			"password": "******",
+			"email":    "******",
+			"n":        float64(1),
+			"inner":    map[string]any{"emails": []any{"******", "plain"}},
+		}},
+		{"msg": "array", "name": "nestedredact", "emails": []any{"******"}},
+		{"msg": "dict", "name": "nestedredact", "user": map[string]any{"db_password": "******", "id": float64(7)}},
+		{"msg": "slog", "name": "nestedredact", "user": map[string]any{"email": "******", "id": float64(7)}},
+		{"msg": "unchanged", "name": "nestedredact", "ids": []any{float64(1), float64(2)}},
+	}, readLogLines(t, logger.opt.GetLogFilePath()))
+}
@@ -0,0 +1,213 @@
+package log
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingOpt configures per message sampling,
+// for every message logs the First entries in each Tick then every Thereafter-th entry
+type SamplingOpt struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+// dropCounter counts entries dropped by sampling, rate limiting and full async buffers
+// and logs the counts from a background goroutine every interval
+type dropCounter struct {
+	sampled    atomic.Uint64
+	limited    atomic.Uint64
+	bufferFull atomic.Uint64
+	interval   time.Duration
+	reporter   zapcore.Core // set once the logger cores are built, enabled at every logger level
+
+	mu             sync.Mutex
+	lastReport     time.Time
+	lastSampled    uint64
+	lastLimited    uint64
+	lastBufferFull uint64
+
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newDropCounter(interval time.Duration) *dropCounter {
+	return &dropCounter{
+		interval:   interval,
+		lastReport: time.Now(),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+}
+
+// start reports dropped counts every interval until stop is called
+func (c *dropCounter) start() {
+	if c.interval <= 0 {
+		close(c.stopped)
+		return
+	}
+	go func() {
+		defer close(c.stopped)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.report()
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the background goroutine and reports counts dropped since the last report
+func (c *dropCounter) stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+		<-c.stopped
+		if c.interval > 0 {
+			c.report()
+		}
+	})
+}
+
+// report logs dropped counts since the last report, the counts are kept for the next report
+// when the report is filtered out by every destination or fails to be written
+func (c *dropCounter) report() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sampled, limited, bufferFull := c.sampled.Load(), c.limited.Load(), c.bufferFull.Load()
+	if sampled == c.lastSampled && limited == c.lastLimited && bufferFull == c.lastBufferFull {
+		return
+	}
+	now := time.Now()
+	ce := c.reporter.Check(zapcore.Entry{Level: zapcore.WarnLevel, Time: now, Message: "log entries dropped"}, nil)
+	if ce == nil {
+		return
+	}
+	// zap reports write errors to the error output instead of returning them
+	errOut := &writeErrors{WriteSyncer: zapcore.Lock(os.Stderr)}
+	ce.ErrorOutput = errOut
+	ce.Write(
+		zap.Uint64("sampled", sampled-c.lastSampled),
+		zap.Uint64("rate_limited", limited-c.lastLimited),
+		zap.Uint64("buffer_full", bufferFull-c.lastBufferFull),
+		zap.Duration("interval", now.Sub(c.lastReport)),
+	)
+	if errOut.failed {
+		return
+	}
+	c.lastReport, c.lastSampled, c.lastLimited, c.lastBufferFull = now, sampled, limited, bufferFull
+}
+
+// writeErrors records whether zap reported a write error and passes the report on
+type writeErrors struct {
+	zapcore.WriteSyncer
+	failed bool
+}
+
+// Write implements io.Writer
+func (w *writeErrors) Write(p []byte) (int, error) {
+	w.failed = true
+	return w.WriteSyncer.Write(p)
+}
+
+// newLimitedCore wraps core with sampling and rate limiting configured in opt,
+// entries at error level and above are never dropped
+func newLimitedCore(core zapcore.Core, opt *LoggerOpt, drops *dropCounter) zapcore.Core {
+	if opt.RateLimit <= 0 && opt.Sampling == nil {
+		return core
+	}
+	limited := core
+	if opt.RateLimit > 0 {
+		limited = &rateLimitCore{Core: limited, limiter: &rateLimiter{limit: opt.RateLimit}, drops: drops}
+	}
+	if opt.Sampling != nil {
+		limited = zapcore.NewSamplerWithOptions(
+			limited,
+			opt.Sampling.Tick,
+			opt.Sampling.First,
+			opt.Sampling.Thereafter,
+			zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+				if dec&zapcore.LogDropped != 0 {
+					drops.sampled.Add(1)
+				}
+			}),
+		)
+	}
+	return &severeCore{Core: core, limited: limited}
+}
+
+// severeCore passes entries below error level through limited and others straight to Core,
+// zap runs the fatal and panic hooks of dropped entries so they must never be dropped
+type severeCore struct {
+	zapcore.Core
+	limited zapcore.Core
+}
+
+// With implements zapcore.Core
+func (c *severeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &severeCore{Core: c.Core.With(fields), limited: c.limited.With(fields)}
+}
+
+// Check implements zapcore.Core
+func (c *severeCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= zapcore.ErrorLevel {
+		return c.Core.Check(entry, ce)
+	}
+	return c.limited.Check(entry, ce)
+}
+
+// rateLimiter allows at most limit entries in every second
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window int64 // current second
+	count  int
+}
+
+func (r *rateLimiter) allow() bool {
+	now := time.Now().Unix()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now != r.window {
+		r.window, r.count = now, 0
+	}
+	if r.count >= r.limit {
+		return false
+	}
+	r.count++
+	return true
+}
+
+// rateLimitCore drops entries over the lines per second limit
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+	drops   *dropCounter
+}
+
+// With implements zapcore.Core
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter, drops: c.drops}
+}
+
+// Check implements zapcore.Core
+func (c *rateLimitCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return ce
+	}
+	if !c.limiter.allow() {
+		c.drops.limited.Add(1)
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSampling(t *testing.T) {
+	logger := newTestLogger(t, "sampling", CommonLogOpt.WithTraceIDEnable(false).
+		WithSampling(time.Minute, 2, 0).
+		WithDropReportInterval(time.Hour))
+
+	for i := 0; i < 10; i++ {
+		logger.Info(context.Background(), "hot path")
+	}
+	logger.Info(context.Background(), "after hot path")
+	// counts dropped since the last report are reported on close
+	assert.Nil(t, logger.Close())
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "hot path", lines[1]["msg"])
+	assert.Equal(t, "after hot path", lines[2]["msg"])
+	assert.Equal(t, "log entries dropped", lines[3]["msg"])
+	assert.Equal(t, float64(8), lines[3]["sampled"])
+	assert.Equal(t, float64(0), lines[3]["rate_limited"])
+}
+
+func TestDropReport(t *testing.T) {
+	logger := newTestLogger(t, "dropreport", CommonLogOpt.WithTraceIDEnable(false).
+		WithLogLevel(zapcore.InfoLevel).
+		WithSampling(time.Minute, 1, 0).
+		WithDropReportInterval(50*time.Millisecond))
+
+	for i := 0; i < 5; i++ {
+		logger.Info(context.Background(), "hot path")
+	}
+	logger.SetLevel(zapcore.ErrorLevel)
+
+	// reported without further logging, although warn is below the logger level
+	assert.Eventually(t, func() bool {
+		return len(readLogLines(t, logger.opt.GetLogFilePath())) == 2
+	}, time.Second, 10*time.Millisecond)
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, "log entries dropped", lines[1]["msg"])
+	assert.Equal(t, float64(4), lines[1]["sampled"])
+}
+
+func TestRateLimit(t *testing.T) {
+	logger := newTestLogger(t, "ratelimit", CommonLogOpt.WithRateLimit(3))
+
+	for i := 0; i < 10; i++ {
+		logger.Info(context.Background(), "line", zap.Int("i", i))
+	}
+
+	// the loop may cross a second boundary
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.LessOrEqual(t, len(lines), 6)
+	assert.Equal(t, uint64(10-len(lines)), logger.drops.limited.Load())
+}
+
+func TestLimitsKeepSevereEntries(t *testing.T) {
+	logger := newTestLogger(t, "severe", CommonLogOpt.WithTraceIDEnable(false).
+		WithRateLimit(1).
+		WithSampling(time.Minute, 1, 0).
+		WithFatalHook(zapcore.WriteThenPanic))
+	ctx := context.Background()
+
+	logger.Info(ctx, "info")
+	logger.Info(ctx, "info")
+	logger.Error(ctx, "error")
+	logger.Error(ctx, "error")
+	assert.PanicsWithValue(t, "fatal", func() { logger.Fatal(ctx, "fatal") })
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	msgs := make([]any, 0, len(lines))
+	for _, line := range lines {
+		msgs = append(msgs, line["msg"])
+	}
+	assert.Equal(t, []any{"info", "error", "error", "fatal"}, msgs)
+}
@@ -0,0 +1,143 @@
+package log
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink is a log destination besides the log file and stdout,
+// a Sink literal writes json at every level enabled by the logger like NewSink
+type Sink struct {
+	Writer   zapcore.WriteSyncer // must be safe for concurrent use
+	Encoding Encoding            // json when empty
+	Level    *zapcore.Level      // minimum level on top of the logger level, nil follows the logger level
+	closer   io.Closer           // writer opened by this package, closed with the logger
+	core     zapcore.Core        // when not nil, entries are written to core instead of Writer
+	leveled  levelWriter         // when not nil, encoded entries are written to leveled instead of Writer
+}
+
+// NewSink new a json sink writing to w at every level enabled by the logger,
+// w is owned by the caller and never closed by the logger, so a sink may be shared by loggers
+func NewSink(w io.Writer) Sink {
+	return Sink{
+		Writer:   zapcore.Lock(zapcore.AddSync(w)),
+		Encoding: EncodingJSON,
+	}
+}
+
+// NewCoreSink new a sink writing entries to core as they are, without encoding them,
+// e.g. to capture entries in memory or to hand them to another zap based library
+func NewCoreSink(core zapcore.Core) Sink {
+	return Sink{core: core}
+}
+
+// StderrSink new a console sink writing to stderr
+func StderrSink() Sink {
+	return NewSink(consoleWriter{os.Stderr}).WithEncoding(EncodingConsole)
+}
+
+// NetworkSink new a json sink writing to a tcp, udp or unix endpoint,
+// the connection is made on first write and made again after a write fails or the logger is closed
+func NetworkSink(network string, address string) Sink {
+	w := &netWriter{network: network, address: address}
+	sink := NewSink(w)
+	sink.closer = w
+	return sink
+}
+
+// WithEncoding setting sink encoding
+func (s Sink) WithEncoding(encoding Encoding) Sink {
+	s.Encoding = encoding
+	return s
+}
+
+// WithLevel setting sink minimum level
+func (s Sink) WithLevel(level zapcore.Level) Sink {
+	s.Level = &level
+	return s
+}
+
+// newCore new a core writing to the sink when both level and sink level are enabled
+// sinks never use colors, their writers are not terminals
+func (s Sink) newCore(opt *LoggerOpt, level zapcore.LevelEnabler) zapcore.Core {
+	if s.core != nil {
+		return newLevelCore(s.core, minLevel(level, s.Level))
+	}
+	if s.leveled != nil {
+		return newLevelWriterCore(newEncoder(s.Encoding, opt, false), s.leveled, minLevel(level, s.Level))
+	}
+	return zapcore.NewCore(newEncoder(s.Encoding, opt, false), s.Writer, minLevel(level, s.Level))
+}
+
+// minLevel enables levels enabled by level and not lower than min, min is ignored when nil
+func minLevel(level zapcore.LevelEnabler, min *zapcore.Level) zapcore.LevelEnabler {
+	if min == nil {
+		return level
+	}
+	m := *min
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= m && level.Enabled(l)
+	})
+}
+
+// close closes the sink writer when this package opened it
+func (s Sink) close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// netDialTimeout timeout of connecting network sinks
+const netDialTimeout = 5 * time.Second
+
+// netWriter writes log lines to a network connection
+type netWriter struct {
+	network string
+	address string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Write implements io.Writer
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, netDialTimeout)
+		if err != nil {
+			return 0, err
+		}
+		w.conn = conn
+	}
+	n, err := w.conn.Write(p)
+	if err != nil { // connect again on next write
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	return n, err
+}
+
+// Sync implements zapcore.WriteSyncer
+func (w *netWriter) Sync() error {
+	return nil
+}
+
+// Close implements io.Closer
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
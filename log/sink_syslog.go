@@ -0,0 +1,50 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogSink new a console sink writing to syslog with tag,
+// an empty network and address connects to the local syslog server.
+// The syslog severity follows the entry level, the connection is made again when writing after the logger is closed
+func SyslogSink(network string, address string, tag string) (Sink, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return Sink{}, err
+	}
+	sink := NewSink(w).WithEncoding(EncodingConsole)
+	sink.leveled = syslogWriter{w}
+	sink.closer = w
+	return sink, nil
+}
+
+// syslogWriter writes entries to syslog with the severity of their level
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+// WriteLevel implements levelWriter
+func (s syslogWriter) WriteLevel(level zapcore.Level, p []byte) error {
+	m := string(p)
+	switch {
+	case level <= zapcore.DebugLevel:
+		return s.w.Debug(m)
+	case level == zapcore.InfoLevel:
+		return s.w.Info(m)
+	case level == zapcore.WarnLevel:
+		return s.w.Warning(m)
+	case level == zapcore.ErrorLevel:
+		return s.w.Err(m)
+	default:
+		return s.w.Crit(m)
+	}
+}
+
+// Sync implements levelWriter, syslog writes are not buffered
+func (s syslogWriter) Sync() error {
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSyslogSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	sink, err := SyslogSink("unixgram", path, "app")
+	assert.Nil(t, err)
+	logger := newTestLogger(t, "syslog", CommonLogOpt.WithFileLog(false).WithLogLevel(zapcore.DebugLevel).WithSinks(sink))
+
+	testcases := []struct {
+		Log      func(ctx context.Context, msg string, fields ...zap.Field)
+		Priority string // facility user is 8
+	}{
+		{logger.Debug, "<15>"},
+		{logger.Info, "<14>"},
+		{logger.Warn, "<12>"},
+		{logger.Error, "<11>"},
+	}
+	buf := make([]byte, 4096)
+	assert.Nil(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for _, testcase := range testcases {
+		testcase.Log(context.Background(), "syslog log")
+		n, _, err := conn.ReadFrom(buf)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(buf[:n]), testcase.Priority), string(buf[:n]))
+		assert.Contains(t, string(buf[:n]), "app")
+		assert.Contains(t, string(buf[:n]), "syslog log")
+	}
+}
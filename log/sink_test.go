@@ -0,0 +1,103 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSinks(t *testing.T) {
+	jsonBuf, consoleBuf, literalBuf := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	logger := newTestLogger(t, "sinks", CommonLogOpt.WithFileLog(false).WithTraceIDEnable(false).
+		WithLogLevel(zapcore.DebugLevel).
+		WithSinks(
+			NewSink(jsonBuf).WithLevel(zapcore.WarnLevel),
+			NewSink(consoleBuf).WithEncoding(EncodingConsole),
+			Sink{Writer: zapcore.AddSync(literalBuf)},
+		))
+
+	logger.Debug(context.Background(), "debug log")
+	logger.Warn(context.Background(), "warn log")
+
+	assert.Nil(t, logger.file)
+	assert.Equal(t, 1, strings.Count(jsonBuf.String(), "\n"))
+	assert.Contains(t, jsonBuf.String(), `"msg":"warn log"`)
+	assert.Equal(t, 2, strings.Count(consoleBuf.String(), "\n"))
+	assert.Contains(t, consoleBuf.String(), "debug log")
+	// a sink without level follows the logger level
+	assert.Contains(t, literalBuf.String(), `"msg":"debug log"`)
+
+	// sink level works on top of logger level
+	logger.SetLevel(zapcore.ErrorLevel)
+	logger.Warn(context.Background(), "filtered warn log")
+	assert.NotContains(t, jsonBuf.String(), "filtered")
+}
+
+func TestSharedSink(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "shared.log"))
+	assert.Nil(t, err)
+	defer f.Close()
+	opt := CommonLogOpt.WithFileLog(false).WithSinks(NewSink(f))
+	first := newTestLogger(t, "sharedfirst", opt)
+	second := newTestLogger(t, "sharedsecond", opt)
+
+	first.Info(context.Background(), "first log")
+	// closing a logger leaves writers of the caller open
+	assert.Nil(t, first.Close())
+	second.Info(context.Background(), "second log")
+	assert.Nil(t, second.Close())
+
+	lines := readLogLines(t, f.Name())
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, "second log", lines[1]["msg"])
+	_, err = f.WriteString("")
+	assert.Nil(t, err)
+}
+
+func TestNetworkSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	logger := newTestLogger(t, "network", CommonLogOpt.WithFileLog(false).
+		WithSinks(NetworkSink("tcp", listener.Addr().String())))
+	logger.Info(context.Background(), "network log")
+
+	assert.Contains(t, <-received, `"msg":"network log"`)
+	assert.Nil(t, logger.Close())
+}
+
+func TestFileLevelAndEncoding(t *testing.T) {
+	logger := newTestLogger(t, "logfmt", CommonLogOpt.WithTraceIDEnable(false).WithCaller(false).
+		WithLogLevel(zapcore.DebugLevel).
+		WithFileLevel(zapcore.WarnLevel).
+		WithFileEncoding(EncodingLogfmt))
+
+	logger.Info(context.Background(), "info log")
+	logger.Warn(context.Background(), "warn log", zap.Int("n", 1))
+
+	content, err := os.ReadFile(logger.opt.GetLogFilePath())
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 1, len(lines))
+	assert.Regexp(t, `^time=\S+ level=warn msg="warn log" name=logfmt n=1$`, lines[0])
+}
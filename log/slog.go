@@ -0,0 +1,158 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler is a slog.Handler writing records to a Logger,
+// so code using log/slog shares the files, sinks and level of the logger
+type SlogHandler struct {
+	logger *Logger
+	fields []zap.Field // fields of WithAttrs outside groups
+	groups []slogGroup // groups of WithGroup, innermost last
+}
+
+// slogGroup is a group of WithGroup with the fields of WithAttrs called after it,
+// groups are written as zap dicts so fields added by hooks stay at the top level
+type slogGroup struct {
+	name   string
+	fields []zap.Field
+}
+
+// NewSlogHandler new a slog handler writing to logger
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// NewSlogLogger new a slog logger writing to the logger named name,
+// the logger is created by GetLogger with opt when it is not registered yet
+func NewSlogLogger(name string, opt *LoggerOpt) *slog.Logger {
+	return slog.New(NewSlogHandler(GetLogger(name, opt)))
+}
+
+// Enabled implements slog.Handler
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.level.Enabled(zapLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var fields []zap.Field
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, attr)
+		return true
+	})
+	// wrap the record fields in the groups from the innermost out, groups without fields are left out
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		group := h.groups[i]
+		if len(group.fields)+len(fields) == 0 {
+			continue
+		}
+		fields = []zap.Field{zap.Dict(group.name, append(group.fields[:len(group.fields):len(group.fields)], fields...)...)}
+	}
+	fields = append(h.fields[:len(h.fields):len(h.fields)], fields...)
+	// the caller is where slog was called instead of this handler
+	h.logger.log(ctx, zapLevel(record.Level), h.logger.callerFunc(record.PC, record.Time), record.Message, fields...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler,
+// attrs are redacted with the record fields when a record is handled
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	child := *h
+	if len(h.groups) == 0 {
+		for _, attr := range attrs {
+			child.fields = appendAttr(child.fields[:len(child.fields):len(child.fields)], attr)
+		}
+		return &child
+	}
+	child.groups = append([]slogGroup(nil), h.groups...)
+	last := &child.groups[len(child.groups)-1]
+	for _, attr := range attrs {
+		last.fields = appendAttr(last.fields[:len(last.fields):len(last.fields)], attr)
+	}
+	return &child
+}
+
+// WithGroup implements slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.groups = append(h.groups[:len(h.groups):len(h.groups)], slogGroup{name: name})
+	return &child
+}
+
+// appendAttr appends attr converted to a zap field
+func appendAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if attr.Key == "" { // inline attrs of a group without key
+			for _, a := range attrs {
+				fields = appendAttr(fields, a)
+			}
+			return fields
+		}
+		group := make([]zap.Field, 0, len(attrs))
+		for _, a := range attrs {
+			group = appendAttr(group, a)
+		}
+		return append(fields, zap.Dict(attr.Key, group...))
+	}
+	return append(fields, attrField(attr))
+}
+
+// attrField converts attr of a non group value to a zap field
+func attrField(attr slog.Attr) zap.Field {
+	value := attr.Value
+	switch value.Kind() {
+	case slog.KindString:
+		return zap.String(attr.Key, value.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, value.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, value.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, value.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, value.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, value.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, value.Time())
+	}
+	if err, ok := value.Any().(error); ok {
+		return zap.NamedError(attr.Key, err)
+	}
+	return zap.Any(attr.Key, value.Any())
+}
+
+// zapLevel maps a slog level to the zap level it falls in,
+// levels above error are logged at error since fatal exits
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogHandler(t *testing.T) {
+	base := newTestLogger(t, "slog", CommonLogOpt.WithLogLevel(zapcore.InfoLevel))
+	logger := slog.New(NewSlogHandler(base))
+	ctx := WithTraceID(context.Background(), "trace")
+
+	logger.DebugContext(ctx, "debug is disabled")
+	logger.InfoContext(ctx, "info", "n", 1, "ok", true, "d", time.Second)
+	logger.With("a", "b").WithGroup("req").WithGroup("empty").Log(ctx, slog.LevelWarn+1, "warn")
+	logger.WithGroup("req").With("method", "GET").ErrorContext(ctx, "error",
+		slog.Group("user", "id", 7), slog.Group("", "inline", "x"), "err", errors.New("boom"))
+
+	lines := readLogLines(t, base.opt.GetLogFilePath())
+	assert.Equal(t, 3, len(lines))
+	assert.Contains(t, lines[2]["stack"], "TestSlogHandler")
+	delete(lines[2], "stack")
+	assert.Equal(t, []map[string]any{
+		{"msg": "info", "name": "slog", "trace_id": "trace", "n": float64(1), "ok": true, "d": float64(time.Second)},
+		{"msg": "warn", "name": "slog", "trace_id": "trace", "a": "b"},
+		{"msg": "error", "name": "slog", "trace_id": "trace", "req": map[string]any{
+			"method": "GET",
+			"user":   map[string]any{"id": float64(7)},
+			"inline": "x",
+			"err":    "boom",
+		}},
+	}, lines)
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	base := newTestLogger(t, "slogcaller", CommonLogOpt.WithTraceIDEnable(false).WithCaller(true))
+	logger := slog.New(NewSlogHandler(base))
+	logger.Info("caller")
+
+	content, err := os.ReadFile(base.opt.GetLogFilePath())
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(content), "/slog_test.go:"), string(content))
+}
+
+func TestZapLevel(t *testing.T) {
+	testcases := []struct {
+		Level slog.Level
+		Want  zapcore.Level
+	}{
+		{slog.LevelDebug - 4, zapcore.DebugLevel},
+		{slog.LevelInfo, zapcore.InfoLevel},
+		{slog.LevelInfo + 2, zapcore.InfoLevel},
+		{slog.LevelWarn, zapcore.WarnLevel},
+		{slog.LevelError + 4, zapcore.ErrorLevel},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, testcase.Want, zapLevel(testcase.Level))
+	}
+}
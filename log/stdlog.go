@@ -0,0 +1,43 @@
+package log
+
+import (
+	"context"
+	stdlog "log"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// stdLogCallerSkip frames between stdLogWriter.Write and the caller of the standard library logger
+const stdLogCallerSkip = 3
+
+// stdLogWriter writes lines of a standard library logger to a Logger
+type stdLogWriter struct {
+	logger *Logger
+	level  zapcore.Level
+}
+
+// Write implements io.Writer
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	w.logger.logAt(context.Background(), w.level, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// NewStdLog new a standard library logger writing to logger at level
+func NewStdLog(logger *Logger, level zapcore.Level) *stdlog.Logger {
+	return stdlog.New(&stdLogWriter{logger: logger.WithCallerSkip(stdLogCallerSkip), level: level}, "", 0)
+}
+
+// RedirectStdLog redirects output of the standard library log package to logger at level,
+// the returned func restores the previous output, prefix and flags
+func RedirectStdLog(logger *Logger, level zapcore.Level) func() {
+	flags, prefix, out := stdlog.Flags(), stdlog.Prefix(), stdlog.Writer()
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+	stdlog.SetOutput(&stdLogWriter{logger: logger.WithCallerSkip(stdLogCallerSkip), level: level})
+	return func() {
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+		stdlog.SetOutput(out)
+	}
+}
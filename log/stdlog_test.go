@@ -0,0 +1,47 @@
+package log
+
+import (
+	"encoding/json"
+	stdlog "log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+// readLogCallers reads the caller of every line of the log file at path
+func readLogCallers(t *testing.T, path string) []string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	var callers []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var fields struct {
+			Caller string `json:"caller"`
+		}
+		assert.Nil(t, json.Unmarshal([]byte(line), &fields))
+		callers = append(callers, fields.Caller[strings.LastIndex(fields.Caller, "/")+1:])
+	}
+	return callers
+}
+
+func TestStdLog(t *testing.T) {
+	logger := newTestLogger(t, "stdlog", CommonLogOpt.WithTraceIDEnable(false))
+
+	restore := RedirectStdLog(logger, zapcore.WarnLevel)
+	stdlog.Printf("redirected %d", 1)
+	restore()
+	NewStdLog(logger, zapcore.InfoLevel).Println("std logger")
+
+	lines := readLogLines(t, logger.opt.GetLogFilePath())
+	assert.Equal(t, []map[string]any{
+		{"msg": "redirected 1", "name": "stdlog"},
+		{"msg": "std logger", "name": "stdlog"},
+	}, lines)
+	callers := readLogCallers(t, logger.opt.GetLogFilePath())
+	assert.True(t, strings.HasPrefix(callers[0], "stdlog_test.go:"), callers)
+	assert.True(t, strings.HasPrefix(callers[1], "stdlog_test.go:"), callers)
+	assert.Equal(t, os.Stderr, stdlog.Writer())
+}
@@ -2,21 +2,71 @@ package log
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"net/http"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceIDFormat decides how NewTraceIDWithCtx generates trace ids
+type TraceIDFormat int32
+
+const (
+	// TraceIDFormatUUID random uuid trace ids, the default
+	TraceIDFormatUUID TraceIDFormat = iota
+	// TraceIDFormatW3C 16 bytes W3C trace ids in lowercase hex
+	TraceIDFormatW3C
+	// TraceIDFormatW3CSpan W3C trace ids carried by a new non-recording span context,
+	// so OTel instrumentation sees the same trace id
+	TraceIDFormatW3CSpan
+)
+
+const (
+	// TraceParentHeader W3C trace context header
+	TraceParentHeader = "traceparent"
+	// RequestIDHeader header carrying trace ids which are not W3C trace ids
+	RequestIDHeader = "X-Request-ID"
 )
 
 var (
 	ctxTraceIdKey = logCtxTraceIdKey{}
+
+	traceIDFormat atomic.Int32
+
+	traceContext = propagation.TraceContext{}
 )
 
 type logCtxTraceIdKey struct{}
 
+// SetTraceIDFormat setting trace id format used by NewTraceIDWithCtx
+func SetTraceIDFormat(format TraceIDFormat) {
+	traceIDFormat.Store(int32(format))
+}
+
 // NewTraceIDWithCtx wrap ctx with traceid
 func NewTraceIDWithCtx(ctx context.Context) context.Context {
-	return context.WithValue(ctx, ctxTraceIdKey, uuid.New().String())
+	switch TraceIDFormat(traceIDFormat.Load()) {
+	case TraceIDFormatW3C:
+		return WithTraceID(ctx, newW3CTraceID().String())
+	case TraceIDFormatW3CSpan:
+		return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: newW3CTraceID(),
+			SpanID:  newW3CSpanID(),
+		}))
+	default:
+		return WithTraceID(ctx, uuid.New().String())
+	}
+}
+
+// WithTraceID wrap ctx with the given traceid
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, ctxTraceIdKey, traceID)
 }
 
 // GetTraceIDWithCtx get trace id from ctx
@@ -32,3 +82,80 @@ func GetTraceIDWithCtx(ctx context.Context) string {
 	}
 	return ""
 }
+
+// InjectTraceID set trace id of ctx into carrier,
+// traceparent is set when the trace id is a W3C trace id and X-Request-ID is always set
+func InjectTraceID(ctx context.Context, carrier propagation.TextMapCarrier) {
+	traceID := GetTraceIDWithCtx(ctx)
+	if traceID == "" {
+		return
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		traceContext.Inject(ctx, carrier)
+	} else if id, err := trace.TraceIDFromHex(traceID); err == nil {
+		// trace id made by ourselves, start the trace with a new parent span id
+		carrier.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-00", id, newW3CSpanID()))
+	}
+	carrier.Set(RequestIDHeader, traceID)
+}
+
+// ExtractTraceID wrap ctx with trace id found in carrier,
+// traceparent takes precedence over X-Request-ID
+func ExtractTraceID(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if ctx := traceContext.Extract(ctx, carrier); trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if requestID := carrier.Get(RequestIDHeader); requestID != "" {
+		return WithTraceID(ctx, requestID)
+	}
+	return ctx
+}
+
+// InjectTraceIDHTTP set trace id of ctx into http header
+func InjectTraceIDHTTP(ctx context.Context, header http.Header) {
+	InjectTraceID(ctx, propagation.HeaderCarrier(header))
+}
+
+// ExtractTraceIDHTTP wrap ctx with trace id found in http header
+func ExtractTraceIDHTTP(ctx context.Context, header http.Header) context.Context {
+	return ExtractTraceID(ctx, propagation.HeaderCarrier(header))
+}
+
+func newW3CTraceID() trace.TraceID {
+	var id trace.TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newW3CSpanID() trace.SpanID {
+	var id trace.SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// getTraceFieldsWithCtx get trace_id, span_id, trace_flags
+// and the baggage members listed in baggageKeys from ctx
+func getTraceFieldsWithCtx(ctx context.Context, baggageKeys []string) []zap.Field {
+	fields := []zap.Field{zap.String("trace_id", GetTraceIDWithCtx(ctx))}
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.HasSpanID() {
+		fields = append(fields,
+			zap.String("span_id", spanContext.SpanID().String()),
+			zap.String("trace_flags", spanContext.TraceFlags().String()),
+		)
+	}
+	if len(baggageKeys) == 0 {
+		return fields
+	}
+	bag := baggage.FromContext(ctx)
+	for _, key := range baggageKeys {
+		if member := bag.Member(key); member.Key() != "" {
+			fields = append(fields, zap.String("baggage."+key, member.Value()))
+		}
+	}
+	return fields
+}
@@ -2,9 +2,13 @@ package log
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 func TestTraceID(t *testing.T) {
@@ -27,3 +31,68 @@ func TestNewTraceIDCtx(t *testing.T) {
 		t.Errorf("NewTraceIdWithCtx didn't generate traceid")
 	}
 }
+
+func TestTraceFields(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	member, _ := baggage.NewMember("tenant", "a")
+	bag, _ := baggage.New(member)
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+
+	assert.Equal(t, []zap.Field{
+		zap.String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		zap.String("span_id", "00f067aa0ba902b7"),
+		zap.String("trace_flags", "01"),
+		zap.String("baggage.tenant", "a"),
+	}, getTraceFieldsWithCtx(ctx, []string{"tenant", "user"}))
+
+	assert.Equal(t, []zap.Field{zap.String("trace_id", "")}, getTraceFieldsWithCtx(context.Background(), nil))
+}
+
+func TestTraceIDFormat(t *testing.T) {
+	defer SetTraceIDFormat(TraceIDFormatUUID)
+
+	SetTraceIDFormat(TraceIDFormatW3C)
+	ctx := NewTraceIDWithCtx(context.Background())
+	_, err := trace.TraceIDFromHex(GetTraceIDWithCtx(ctx))
+	assert.Nil(t, err)
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	SetTraceIDFormat(TraceIDFormatW3CSpan)
+	ctx = NewTraceIDWithCtx(context.Background())
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.True(t, spanContext.IsValid())
+	assert.Equal(t, spanContext.TraceID().String(), GetTraceIDWithCtx(ctx))
+}
+
+func TestInjectExtractTraceIDHTTP(t *testing.T) {
+	testcases := []struct {
+		ctx             context.Context
+		WantTraceParent bool
+	}{
+		{WithTraceID(context.Background(), "1234"), false},
+		{WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736"), true},
+		{trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: newW3CTraceID(),
+			SpanID:  newW3CSpanID(),
+		})), true},
+	}
+
+	for _, testcase := range testcases {
+		header := http.Header{}
+		InjectTraceIDHTTP(testcase.ctx, header)
+		assert.Equal(t, testcase.WantTraceParent, header.Get(TraceParentHeader) != "")
+		ctx := ExtractTraceIDHTTP(context.Background(), header)
+		assert.Equal(t, GetTraceIDWithCtx(testcase.ctx), GetTraceIDWithCtx(ctx))
+	}
+
+	header := http.Header{}
+	InjectTraceIDHTTP(context.Background(), header)
+	assert.Empty(t, header)
+	assert.Equal(t, "", GetTraceIDWithCtx(ExtractTraceIDHTTP(context.Background(), header)))
+}
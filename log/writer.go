@@ -0,0 +1,334 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// OverflowPolicy decides what an async writer does when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until the buffer has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop drops the entry, dropped entries are counted in the drop report
+	OverflowDrop
+)
+
+// AsyncOpt configures asynchronous log file writes
+type AsyncOpt struct {
+	BufferSize    int            // max buffered entries
+	FlushInterval time.Duration  // interval to flush written entries to the file
+	Policy        OverflowPolicy // what to do when the buffer is full
+}
+
+var errWriterClosed = errors.New("log writer closed")
+
+// RotationPeriod rotates the log file at the start of every period besides rotating by size
+type RotationPeriod string
+
+const (
+	// RotateNone only rotates the log file by size
+	RotateNone RotationPeriod = ""
+	// RotateHourly rotates the log file at the start of every hour
+	RotateHourly RotationPeriod = "hourly"
+	// RotateDaily rotates the log file at midnight
+	RotateDaily RotationPeriod = "daily"
+)
+
+// valid reports whether p is a known rotation period
+func (p RotationPeriod) valid() bool {
+	switch p {
+	case RotateNone, RotateHourly, RotateDaily:
+		return true
+	}
+	return false
+}
+
+// backupTimeFormat is the timestamp layout of lumberjack backup file names,
+// backups must keep it so lumberjack still cleans and compresses them
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// fileWriter is a lumberjack backed log file writer
+// whose retention can be changed while the logger is in use
+type fileWriter struct {
+	mu        sync.Mutex
+	lj        *lumberjack.Logger
+	rotation  RotationPeriod
+	compress  bool
+	localTime bool
+	now       func() time.Time
+	next      time.Time // start of the next rotation period, zero before the first write
+}
+
+func newFileWriter(filename string, opt *LoggerOpt) *fileWriter {
+	w := &fileWriter{
+		rotation:  opt.Rotation,
+		compress:  opt.Compress,
+		localTime: opt.LocalTime,
+		now:       time.Now,
+	}
+	w.lj = w.newLumberjack(filename, opt.MaxSize, opt.MaxBackups, opt.MaxAge)
+	return w
+}
+
+func (w *fileWriter) newLumberjack(filename string, maxSize int, maxBackups int, maxAge int) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+		LocalTime:  w.localTime,
+		Compress:   w.compress,
+	}
+}
+
+// Write implements io.Writer
+func (w *fileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.rotateByTime(); err != nil {
+		return 0, err
+	}
+	return w.lj.Write(p)
+}
+
+// Sync implements zapcore.WriteSyncer
+func (w *fileWriter) Sync() error {
+	return nil
+}
+
+// Close closes the log file, it is reopened by the next write
+func (w *fileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lj.Close()
+}
+
+// retention returns current max size, max backups and max age
+func (w *fileWriter) retention() (int, int, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lj.MaxSize, w.lj.MaxBackups, w.lj.MaxAge
+}
+
+// setRetention swaps in a new lumberjack logger with the given retention,
+// lumberjack reads its settings from a background goroutine
+// so they can not be changed in place
+func (w *fileWriter) setRetention(maxSize int, maxBackups int, maxAge int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.lj.MaxSize == maxSize && w.lj.MaxBackups == maxBackups && w.lj.MaxAge == maxAge {
+		return nil
+	}
+	err := w.lj.Close()
+	w.lj = w.newLumberjack(w.lj.Filename, maxSize, maxBackups, maxAge)
+	return err
+}
+
+// rotateByTime rotates the log file when a new period started since the last write,
+// a file left by a previous process is rotated on the first write when it is from an earlier period
+func (w *fileWriter) rotateByTime() error {
+	if w.rotation == RotateNone {
+		return nil
+	}
+	now := w.now()
+	if w.next.IsZero() {
+		w.next = w.nextPeriod(w.periodStart(now))
+		info, err := os.Stat(w.lj.Filename)
+		if err != nil || info.Size() == 0 {
+			return nil
+		}
+		if start := w.periodStart(info.ModTime()); start.Before(w.periodStart(now)) {
+			return w.rotate(start)
+		}
+		return nil
+	}
+	if now.Before(w.next) {
+		return nil
+	}
+	// the file holds entries of the period of the last write, periods without writes may follow it
+	last := w.prevPeriod(w.next)
+	w.next = w.nextPeriod(w.periodStart(now))
+	return w.rotate(last)
+}
+
+// rotate moves the log file to a backup stamped with the start of its period,
+// lumberjack then opens a new file and compresses and removes old backups
+func (w *fileWriter) rotate(period time.Time) error {
+	if err := w.lj.Close(); err != nil {
+		return err
+	}
+	filename := w.lj.Filename
+	ext := filepath.Ext(filename)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(filename, ext), period.Format(backupTimeFormat), ext)
+	if err := os.Rename(filename, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return w.lj.Rotate()
+}
+
+// periodStart returns the start of the period t is in
+func (w *fileWriter) periodStart(t time.Time) time.Time {
+	if w.localTime {
+		t = t.Local()
+	} else {
+		t = t.UTC()
+	}
+	if w.rotation == RotateHourly {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (w *fileWriter) nextPeriod(start time.Time) time.Time {
+	if w.rotation == RotateHourly {
+		return start.Add(time.Hour)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func (w *fileWriter) prevPeriod(start time.Time) time.Time {
+	if w.rotation == RotateHourly {
+		return start.Add(-time.Hour)
+	}
+	return start.AddDate(0, 0, -1)
+}
+
+// consoleWriter writes to stdout or stderr,
+// syncing them fails on terminals and pipes so Sync does nothing
+type consoleWriter struct {
+	io.Writer
+}
+
+// Sync implements zapcore.WriteSyncer
+func (w consoleWriter) Sync() error {
+	return nil
+}
+
+// asyncWriter buffers log entries in memory and writes them from a background goroutine
+type asyncWriter struct {
+	out    io.Writer
+	policy OverflowPolicy
+	drops  *dropCounter
+
+	entries chan []byte
+	syncs   chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+
+	mu       sync.RWMutex // held for writing by Close, so no entry is sent after done is closed
+	closed   bool
+	closeErr error // error of the final flush, read after stopped is closed
+}
+
+func newAsyncWriter(out io.Writer, opt AsyncOpt, drops *dropCounter) *asyncWriter {
+	bufferSize, flushInterval := opt.BufferSize, opt.FlushInterval
+	if bufferSize <= 0 {
+		bufferSize = 1024
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	w := &asyncWriter{
+		out:     out,
+		policy:  opt.Policy,
+		drops:   drops,
+		entries: make(chan []byte, bufferSize),
+		syncs:   make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run(flushInterval)
+	return w
+}
+
+// Write implements io.Writer, p is copied since zap reuses its buffers
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	entry := append([]byte(nil), p...)
+	if w.policy == OverflowDrop {
+		select {
+		case w.entries <- entry:
+		default:
+			w.drops.bufferFull.Add(1)
+		}
+		return len(p), nil
+	}
+	w.entries <- entry
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer, it returns once buffered entries are written to the file
+func (w *asyncWriter) Sync() error {
+	ack := make(chan error)
+	select {
+	case w.syncs <- ack:
+		return <-ack
+	case <-w.stopped:
+		return nil
+	}
+}
+
+// Close writes buffered entries and stops the background goroutine
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mu.Unlock()
+	<-w.stopped
+	return w.closeErr
+}
+
+func (w *asyncWriter) run(flushInterval time.Duration) {
+	defer close(w.stopped)
+	buf := bufio.NewWriter(w.out)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	flush := func() error {
+		err := buf.Flush()
+		if err != nil { // bufio keeps the error, start over with an empty buffer
+			buf.Reset(w.out)
+		}
+		return err
+	}
+	// drain writes entries already buffered then flushes
+	drain := func() error {
+		for {
+			select {
+			case entry := <-w.entries:
+				_, _ = buf.Write(entry)
+			default:
+				return flush()
+			}
+		}
+	}
+	for {
+		select {
+		case entry := <-w.entries:
+			_, _ = buf.Write(entry)
+		case <-ticker.C:
+			_ = flush()
+		case ack := <-w.syncs:
+			ack <- drain()
+		case <-w.done:
+			w.closeErr = drain()
+			return
+		}
+	}
+}
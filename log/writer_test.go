@@ -0,0 +1,165 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingWriter blocks writes until unblocked
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriterDrop(t *testing.T) {
+	out := &blockingWriter{unblock: make(chan struct{})}
+	drops := newDropCounter(0)
+	w := newAsyncWriter(out, AsyncOpt{BufferSize: 2, FlushInterval: time.Hour, Policy: OverflowDrop}, drops)
+
+	// bufio keeps the first entries in memory, fill the channel until entries are dropped
+	for i := 0; i < 10000 && drops.bufferFull.Load() == 0; i++ {
+		_, _ = w.Write([]byte("entry\n"))
+	}
+	assert.NotZero(t, drops.bufferFull.Load())
+
+	close(out.unblock)
+	assert.Nil(t, w.Sync())
+	written := out.String()
+	assert.NotEmpty(t, written)
+	assert.Nil(t, w.Close())
+	assert.Equal(t, written, out.String())
+
+	_, err := w.Write([]byte("after close\n"))
+	assert.Equal(t, errWriterClosed, err)
+	assert.Nil(t, w.Sync())
+}
+
+func TestAsyncLogger(t *testing.T) {
+	logger := newTestLogger(t, "async", CommonLogOpt.WithTraceIDEnable(false).
+		WithAsync(16, time.Hour, OverflowBlock))
+
+	for i := 0; i < 100; i++ {
+		logger.Info(context.Background(), "async log")
+	}
+	assert.Nil(t, logger.Sync())
+	assert.Equal(t, 100, len(readLogLines(t, logger.opt.GetLogFilePath())))
+
+	logger.Info(context.Background(), "last async log")
+	assert.Nil(t, logger.Close())
+	assert.Equal(t, 101, len(readLogLines(t, logger.opt.GetLogFilePath())))
+}
+
+// backups lists rotated files of the log file in dir
+func backups(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFileWriterTimeRotation(t *testing.T) {
+	testcases := []struct {
+		Rotation RotationPeriod
+		Writes   []time.Time
+		Want     []string
+	}{
+		{
+			Rotation: RotateDaily,
+			Writes: []time.Time{
+				time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 1, 23, 59, 0, 0, time.UTC),
+				time.Date(2024, 5, 2, 0, 1, 0, 0, time.UTC),
+				time.Date(2024, 5, 4, 8, 0, 0, 0, time.UTC),
+			},
+			Want: []string{"app-2024-05-01T00-00-00.000.log", "app-2024-05-02T00-00-00.000.log"},
+		},
+		{
+			Rotation: RotateHourly,
+			Writes: []time.Time{
+				time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+				time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC),
+			},
+			Want: []string{"app-2024-05-01T10-00-00.000.log"},
+		},
+		{
+			Rotation: RotateNone,
+			Writes: []time.Time{
+				time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		dir := t.TempDir()
+		// no max age, backups dated in the past would be removed
+		opt := CommonLogOpt.WithLogRetention(15, 0, 0).WithLogRotation(testcase.Rotation, false, false)
+		w := newFileWriter(filepath.Join(dir, "app.log"), &opt)
+		for _, now := range testcase.Writes {
+			w.now = func() time.Time { return now }
+			_, err := w.Write([]byte(now.String() + "\n"))
+			assert.Nil(t, err)
+		}
+		assert.Nil(t, w.Close())
+		assert.Equal(t, testcase.Want, backups(t, dir), testcase.Rotation)
+
+		// the current file only holds entries of the latest period
+		data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+		assert.Nil(t, err)
+		last := testcase.Writes[len(testcase.Writes)-1]
+		if testcase.Rotation != RotateNone {
+			assert.Equal(t, last.String()+"\n", string(data))
+		}
+	}
+}
+
+func TestFileWriterRotatesStaleFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	assert.Nil(t, os.WriteFile(filename, []byte("yesterday\n"), 0o644))
+	yesterday := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	assert.Nil(t, os.Chtimes(filename, yesterday, yesterday))
+
+	opt := CommonLogOpt.WithLogRetention(15, 0, 0).WithLogRotation(RotateDaily, true, false)
+	w := newFileWriter(filename, &opt)
+	w.now = func() time.Time { return yesterday.AddDate(0, 0, 1) }
+	_, err := w.Write([]byte("today\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	// lumberjack compresses backups in the background
+	assert.Eventually(t, func() bool {
+		names := backups(t, dir)
+		return len(names) == 1 && names[0] == "app-2024-05-01T00-00-00.000.log.gz"
+	}, 5*time.Second, 10*time.Millisecond)
+}